@@ -0,0 +1,308 @@
+package httputils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-log/log"
+	"github.com/gorilla/mux"
+)
+
+// AccessLogFormat ...
+//
+// It specifies a format of access log entries.
+//
+type AccessLogFormat int
+
+// ...
+//
+const (
+	// Apache Common Log Format
+	CommonLogFormat AccessLogFormat = iota
+	// Apache Combined Log Format
+	CombinedLogFormat
+	// key=value pairs, see https://brandur.org/logfmt
+	LogfmtLogFormat
+	// one JSON object per entry
+	JSONLogFormat
+)
+
+const defaultRequestIDHeader = "X-Request-ID"
+
+// the Apache HTTP Server escapes only these characters in quoted fields
+var clfEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// AccessLogFilter ...
+//
+// It decides whether the request should be logged. It should return false
+// to skip the request.
+//
+type AccessLogFilter func(request *http.Request) bool
+
+// AccessLogPathFilter ...
+//
+// It returns the AccessLogFilter that skips requests with the specified paths
+// (e.g. health checks).
+//
+func AccessLogPathFilter(excludedPaths ...string) AccessLogFilter {
+	return func(request *http.Request) bool {
+		for _, excludedPath := range excludedPaths {
+			if request.URL.Path == excludedPath {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// AccessLogOption ...
+//
+// It sets an optional parameter of the AccessLogMiddleware() middleware.
+//
+type AccessLogOption func(options *accessLogOptions)
+
+type accessLogOptions struct {
+	format          AccessLogFormat
+	filter          AccessLogFilter
+	requestIDHeader string
+	clock           func() time.Time
+}
+
+// WithAccessLogFormat ...
+//
+// It sets the format of access log entries. By default,
+// the CommonLogFormat format is used.
+//
+func WithAccessLogFormat(format AccessLogFormat) AccessLogOption {
+	return func(options *accessLogOptions) {
+		options.format = format
+	}
+}
+
+// WithAccessLogFilter ...
+//
+// It sets the filter of logged requests. By default, all requests are logged.
+//
+func WithAccessLogFilter(filter AccessLogFilter) AccessLogOption {
+	return func(options *accessLogOptions) {
+		options.filter = filter
+	}
+}
+
+// WithAccessLogRequestIDHeader ...
+//
+// It sets the name of the header containing the request ID. The header
+// is looked up in the request first and then in the response. By default,
+// the X-Request-ID header is used.
+//
+func WithAccessLogRequestIDHeader(name string) AccessLogOption {
+	return func(options *accessLogOptions) {
+		options.requestIDHeader = name
+	}
+}
+
+// AccessLogMiddleware ...
+//
+// It's a middleware that logs each request via the provided log.Logger
+// interface after the request has been handled. An entry contains the method,
+// the path, the status code, the response size, the duration, the remote
+// address, the user agent and the request ID (some of them are omitted
+// in the Apache formats according to their specification).
+//
+// The response data is obtained via the CatchingResponseWriter structure.
+//
+func AccessLogMiddleware(
+	logger log.Logger,
+	options ...AccessLogOption,
+) mux.MiddlewareFunc {
+	accessLogOptions := accessLogOptions{
+		format:          CommonLogFormat,
+		filter:          func(request *http.Request) bool { return true },
+		requestIDHeader: defaultRequestIDHeader,
+		clock:           time.Now,
+	}
+	for _, option := range options {
+		option(&accessLogOptions)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(
+			writer http.ResponseWriter,
+			request *http.Request,
+		) {
+			if !accessLogOptions.filter(request) {
+				next.ServeHTTP(writer, request)
+				return
+			}
+
+			// the next handlers can change the request URL,
+			// so its parts should be saved beforehand
+			entry := accessLogEntry{
+				startTime:  accessLogOptions.clock(),
+				method:     request.Method,
+				requestURI: request.URL.RequestURI(),
+				path:       request.URL.Path,
+				protocol:   request.Proto,
+				remoteAddr: request.RemoteAddr,
+				referer:    request.Referer(),
+				userAgent:  request.UserAgent(),
+				requestID:  request.Header.Get(accessLogOptions.requestIDHeader),
+			}
+			if username, _, ok := request.BasicAuth(); ok {
+				entry.username = username
+			}
+
+			catchingWriter := NewCatchingResponseWriter(writer)
+			next.ServeHTTP(catchingWriter.WithOptionalInterfaces(), request)
+
+			entry.duration = accessLogOptions.clock().Sub(entry.startTime)
+			entry.statusCode = catchingWriter.StatusCode()
+			if !catchingWriter.HeaderWritten() {
+				// the http.Server structure sends this status code implicitly
+				entry.statusCode = http.StatusOK
+			}
+			entry.size = catchingWriter.BytesWritten()
+			if entry.requestID == "" {
+				entry.requestID =
+					writer.Header().Get(accessLogOptions.requestIDHeader)
+			}
+
+			logger.Log(entry.format(accessLogOptions.format))
+		})
+	}
+}
+
+type accessLogEntry struct {
+	startTime  time.Time
+	duration   time.Duration
+	method     string
+	requestURI string
+	path       string
+	protocol   string
+	statusCode int
+	size       int64
+	remoteAddr string
+	username   string
+	referer    string
+	userAgent  string
+	requestID  string
+}
+
+func (entry accessLogEntry) format(format AccessLogFormat) string {
+	switch format {
+	case CombinedLogFormat:
+		return entry.formatCombined()
+	case LogfmtLogFormat:
+		return entry.formatLogfmt()
+	case JSONLogFormat:
+		return entry.formatJSON()
+	default:
+		return entry.formatCommon()
+	}
+}
+
+func (entry accessLogEntry) formatCommon() string {
+	host, _, err := net.SplitHostPort(entry.remoteAddr)
+	if err != nil {
+		host = entry.remoteAddr
+	}
+
+	size := "-"
+	if entry.size != 0 {
+		size = strconv.FormatInt(entry.size, 10)
+	}
+
+	return fmt.Sprintf(
+		"%s - %s [%s] \"%s %s %s\" %d %s",
+		orDash(host),
+		orDash(entry.username),
+		entry.startTime.Format("02/Jan/2006:15:04:05 -0700"),
+		entry.method,
+		entry.requestURI,
+		entry.protocol,
+		entry.statusCode,
+		size,
+	)
+}
+
+func (entry accessLogEntry) formatCombined() string {
+	return fmt.Sprintf(
+		"%s \"%s\" \"%s\"",
+		entry.formatCommon(),
+		orDash(clfEscaper.Replace(entry.referer)),
+		orDash(clfEscaper.Replace(entry.userAgent)),
+	)
+}
+
+func (entry accessLogEntry) formatLogfmt() string {
+	pairs := []struct {
+		key   string
+		value string
+	}{
+		{"time", entry.startTime.Format(time.RFC3339)},
+		{"method", entry.method},
+		{"path", entry.path},
+		{"status", strconv.Itoa(entry.statusCode)},
+		{"size", strconv.FormatInt(entry.size, 10)},
+		{"duration", entry.duration.String()},
+		{"remote_addr", entry.remoteAddr},
+		{"user_agent", entry.userAgent},
+		{"request_id", entry.requestID},
+	}
+
+	formattedPairs := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		value := pair.value
+		if value == "" || strings.ContainsAny(value, " \"=\\") {
+			value = strconv.Quote(value)
+		}
+
+		formattedPairs = append(formattedPairs, pair.key+"="+value)
+	}
+
+	return strings.Join(formattedPairs, " ")
+}
+
+func (entry accessLogEntry) formatJSON() string {
+	bytes, err := json.Marshal(struct {
+		Time       string `json:"time"`
+		Method     string `json:"method"`
+		Path       string `json:"path"`
+		Status     int    `json:"status"`
+		Size       int64  `json:"size"`
+		Duration   string `json:"duration"`
+		RemoteAddr string `json:"remote_addr"`
+		UserAgent  string `json:"user_agent"`
+		RequestID  string `json:"request_id"`
+	}{
+		Time:       entry.startTime.Format(time.RFC3339),
+		Method:     entry.method,
+		Path:       entry.path,
+		Status:     entry.statusCode,
+		Size:       entry.size,
+		Duration:   entry.duration.String(),
+		RemoteAddr: entry.remoteAddr,
+		UserAgent:  entry.userAgent,
+		RequestID:  entry.requestID,
+	})
+	if err != nil {
+		// it should never happen, because all the fields are marshallable
+		return entry.formatLogfmt()
+	}
+
+	return string(bytes)
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
@@ -0,0 +1,268 @@
+package httputils
+
+import (
+	"fmt"
+	stdlog "log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-log/log/print"
+	"github.com/stretchr/testify/mock"
+)
+
+func ExampleAccessLogMiddleware() {
+	// use the standard logger for access logging
+	logger := stdlog.New(os.Stdout, "", 0)
+	accessLogMiddleware := AccessLogMiddleware(
+		// wrap the standard logger via the github.com/go-log/log package
+		print.New(logger),
+		WithAccessLogFormat(CombinedLogFormat),
+		WithAccessLogFilter(AccessLogPathFilter("/health")),
+	)
+
+	var handler http.Handler // nolint: staticcheck
+	handler = http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(writer, "Hello, world!") // nolint: errcheck
+	})
+	handler = accessLogMiddleware(handler)
+
+	http.Handle("/", handler)
+	logger.Fatal(http.ListenAndServe(":8080", nil))
+}
+
+func TestAccessLogMiddleware(test *testing.T) {
+	type args struct {
+		options []AccessLogOption
+	}
+	type middlewareArgs struct {
+		next http.Handler
+	}
+	type handlerArgs struct {
+		request *http.Request
+	}
+
+	startTime := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+	withClock := func(options *accessLogOptions) {
+		currentTime := startTime
+		options.clock = func() time.Time {
+			result := currentTime
+			currentTime = currentTime.Add(1500 * time.Millisecond)
+
+			return result
+		}
+	}
+	newRequest := func() *http.Request {
+		request := httptest.NewRequest(
+			http.MethodPost,
+			"http://example.com/path?key=value",
+			nil,
+		)
+		request.Header.Set("Referer", "http://example.com/")
+		request.Header.Set("User-Agent", "Test Agent")
+		request.Header.Set("X-Request-ID", "100500")
+
+		return request
+	}
+	writingHandler := http.HandlerFunc(func(
+		writer http.ResponseWriter,
+		_ *http.Request,
+	) {
+		writer.WriteHeader(http.StatusCreated)
+		writer.Write([]byte("test")) // nolint: errcheck
+	})
+
+	for _, data := range []struct {
+		name           string
+		args           args
+		middlewareArgs middlewareArgs
+		handlerArgs    handlerArgs
+		wantLog        []string
+	}{
+		{
+			name: "Common Log Format",
+			args: args{
+				options: []AccessLogOption{withClock},
+			},
+			middlewareArgs: middlewareArgs{
+				next: writingHandler,
+			},
+			handlerArgs: handlerArgs{
+				request: newRequest(),
+			},
+			wantLog: []string{
+				`192.0.2.1 - - [02/Jan/2006:15:04:05 +0000] ` +
+					`"POST /path?key=value HTTP/1.1" 201 4`,
+			},
+		},
+		{
+			name: "Common Log Format/with the user and without the body",
+			args: args{
+				options: []AccessLogOption{withClock},
+			},
+			middlewareArgs: middlewareArgs{
+				next: http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}),
+			},
+			handlerArgs: handlerArgs{
+				request: func() *http.Request {
+					request := newRequest()
+					request.SetBasicAuth("user", "password")
+
+					return request
+				}(),
+			},
+			wantLog: []string{
+				`192.0.2.1 - user [02/Jan/2006:15:04:05 +0000] ` +
+					`"POST /path?key=value HTTP/1.1" 200 -`,
+			},
+		},
+		{
+			name: "Combined Log Format",
+			args: args{
+				options: []AccessLogOption{
+					withClock,
+					WithAccessLogFormat(CombinedLogFormat),
+				},
+			},
+			middlewareArgs: middlewareArgs{
+				next: writingHandler,
+			},
+			handlerArgs: handlerArgs{
+				request: newRequest(),
+			},
+			wantLog: []string{
+				`192.0.2.1 - - [02/Jan/2006:15:04:05 +0000] ` +
+					`"POST /path?key=value HTTP/1.1" 201 4 ` +
+					`"http://example.com/" "Test Agent"`,
+			},
+		},
+		{
+			name: "Combined Log Format/with the missing and special values",
+			args: args{
+				options: []AccessLogOption{
+					withClock,
+					WithAccessLogFormat(CombinedLogFormat),
+				},
+			},
+			middlewareArgs: middlewareArgs{
+				next: writingHandler,
+			},
+			handlerArgs: handlerArgs{
+				request: func() *http.Request {
+					request := newRequest()
+					request.Header.Del("Referer")
+					request.Header.Set("User-Agent", `Test "Agent" \ ÿ`)
+
+					return request
+				}(),
+			},
+			wantLog: []string{
+				`192.0.2.1 - - [02/Jan/2006:15:04:05 +0000] ` +
+					`"POST /path?key=value HTTP/1.1" 201 4 ` +
+					`"-" "Test \"Agent\" \\ ÿ"`,
+			},
+		},
+		{
+			name: "logfmt",
+			args: args{
+				options: []AccessLogOption{
+					withClock,
+					WithAccessLogFormat(LogfmtLogFormat),
+				},
+			},
+			middlewareArgs: middlewareArgs{
+				next: writingHandler,
+			},
+			handlerArgs: handlerArgs{
+				request: newRequest(),
+			},
+			wantLog: []string{
+				`time=2006-01-02T15:04:05Z method=POST path=/path status=201 size=4 ` +
+					`duration=1.5s remote_addr=192.0.2.1:1234 ` +
+					`user_agent="Test Agent" request_id=100500`,
+			},
+		},
+		{
+			name: "JSON",
+			args: args{
+				options: []AccessLogOption{
+					withClock,
+					WithAccessLogFormat(JSONLogFormat),
+				},
+			},
+			middlewareArgs: middlewareArgs{
+				next: writingHandler,
+			},
+			handlerArgs: handlerArgs{
+				request: newRequest(),
+			},
+			wantLog: []string{
+				`{"time":"2006-01-02T15:04:05Z","method":"POST","path":"/path",` +
+					`"status":201,"size":4,"duration":"1.5s",` +
+					`"remote_addr":"192.0.2.1:1234","user_agent":"Test Agent",` +
+					`"request_id":"100500"}`,
+			},
+		},
+		{
+			name: "with the request ID in the response",
+			args: args{
+				options: []AccessLogOption{
+					withClock,
+					WithAccessLogFormat(LogfmtLogFormat),
+					WithAccessLogRequestIDHeader("X-Trace-ID"),
+				},
+			},
+			middlewareArgs: middlewareArgs{
+				next: http.HandlerFunc(func(
+					writer http.ResponseWriter,
+					request *http.Request,
+				) {
+					// the path change should not affect the entry
+					request.URL.Path = "/"
+
+					writer.Header().Set("X-Trace-ID", "23")
+					writer.WriteHeader(http.StatusNoContent)
+				}),
+			},
+			handlerArgs: handlerArgs{
+				request: newRequest(),
+			},
+			wantLog: []string{
+				`time=2006-01-02T15:04:05Z method=POST path=/path status=204 size=0 ` +
+					`duration=1.5s remote_addr=192.0.2.1:1234 ` +
+					`user_agent="Test Agent" request_id=23`,
+			},
+		},
+		{
+			name: "with the filter",
+			args: args{
+				options: []AccessLogOption{
+					withClock,
+					WithAccessLogFilter(AccessLogPathFilter("/health", "/path")),
+				},
+			},
+			middlewareArgs: middlewareArgs{
+				next: writingHandler,
+			},
+			handlerArgs: handlerArgs{
+				request: newRequest(),
+			},
+			wantLog: nil,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			logger := new(MockLogger)
+			for _, entry := range data.wantLog {
+				logger.On("Log", entry).Return()
+			}
+
+			middleware := AccessLogMiddleware(logger, data.args.options...)
+			handler := middleware(data.middlewareArgs.next)
+			handler.ServeHTTP(httptest.NewRecorder(), data.handlerArgs.request)
+
+			mock.AssertExpectationsForObjects(test, logger)
+		})
+	}
+}
@@ -0,0 +1,319 @@
+package httputils
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrUnsupportedType ...
+//
+// It's returned (in a wrapped form) by the BindRequest() function when a field
+// has a type that can't be parsed from a string. It's an error of the caller,
+// so it's resolved to the http.StatusInternalServerError status code (see
+// the ResolveHTTPError() function).
+//
+var ErrUnsupportedType = errors.New("the type is unsupported")
+
+// BindingError ...
+//
+// It lists problems with request parameters found by the BindRequest()
+// function. It's resolved to the http.StatusBadRequest status code (see
+// the ResolveHTTPError() function), and the problems are rendered
+// as the "errors" member by the WriteJSONError() and WriteProblem() functions.
+//
+type BindingError struct {
+	Errors []ParameterError
+}
+
+// BindingOption ...
+//
+// It sets an optional parameter of the BindRequest() function.
+//
+type BindingOption func(options *bindingOptions)
+
+type bindingOptions struct {
+	pathParameterSource PathParameterSource
+}
+
+type bindingSource struct {
+	tag       string
+	getValues func(request *http.Request, name string) ([]string, error)
+}
+
+// WithBindingPathParameterSource ...
+//
+// It sets the source of path parameters for the "path" tag. By default,
+// it's the MuxPathParameterSource variable.
+//
+func WithBindingPathParameterSource(
+	pathParameterSource PathParameterSource,
+) BindingOption {
+	return func(options *bindingOptions) {
+		options.pathParameterSource = pathParameterSource
+	}
+}
+
+// BindRequest ...
+//
+// It fills fields of the structure from request parameters according to tags
+// of the fields. The data should be a non-nil pointer to the structure.
+//
+// The supported tags and the corresponding sources:
+//
+//   - path: a path parameter extracted via the github.com/gorilla/mux package
+//     by default (see the WithBindingPathParameterSource() option);
+//   - query: a parameter from the query part of the request URL;
+//   - header: a header;
+//   - cookie: a cookie;
+//   - form: a parameter from the body of the request (see
+//     the http.Request.PostForm field); to support multipart forms,
+//     the http.Request.ParseMultipartForm() method should be called beforehand.
+//
+// A field may have several tags; in this case, the sources are checked
+// in the order listed above until a value is found. If no value is found,
+// the field stays unchanged (so it may hold the default value). Fields
+// of embedded structures without tags are filled recursively.
+//
+// The supported field types are the basic ones (strings, booleans, integers
+// and floating-point numbers), the time.Duration type, types with
+// the registered parser (see the RegisterParameterParser() function), types
+// implementing the encoding.TextUnmarshaler interface (e.g. the time.Time
+// type in the RFC 3339 format), pointers to them and slices of them (filled
+// with all values of the parameter). Values are parsed strictly, i.e.
+// trailing input is an error.
+//
+// Errors of the parsing are collected for all fields and are returned
+// as the BindingError error.
+//
+func BindRequest(
+	request *http.Request,
+	data interface{},
+	options ...BindingOption,
+) error {
+	bindingOptions := bindingOptions{pathParameterSource: MuxPathParameterSource}
+	for _, option := range options {
+		option(&bindingOptions)
+	}
+
+	dataReflection := reflect.ValueOf(data)
+	if dataReflection.Kind() != reflect.Ptr || dataReflection.IsNil() ||
+		dataReflection.Elem().Kind() != reflect.Struct {
+		return errors.New(
+			"the data is incorrect: it should be a non-nil pointer to a structure",
+		)
+	}
+
+	var bindingErr BindingError
+	err := bindStructure(
+		request,
+		newBindingSources(bindingOptions),
+		dataReflection.Elem(),
+		&bindingErr,
+	)
+	if err != nil {
+		return err
+	}
+	if len(bindingErr.Errors) != 0 {
+		return bindingErr
+	}
+
+	return nil
+}
+
+// StatusCode ...
+//
+// It returns the http.StatusBadRequest status code. It implements
+// the StatusCodeProvider interface.
+//
+func (err BindingError) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+// Problem ...
+//
+// It returns problem details with the "errors" extension member. It implements
+// the ProblemProvider interface.
+//
+func (err BindingError) Problem() Problem {
+	return Problem{
+		Status:     err.StatusCode(),
+		Extensions: map[string]interface{}{"errors": err.fieldErrors()},
+	}
+}
+
+func (err BindingError) Error() string {
+	messages := make([]string, 0, len(err.Errors))
+	for _, parameterErr := range err.Errors {
+		messages = append(messages, parameterErr.Error())
+	}
+
+	return "unable to bind the request: " + strings.Join(messages, "; ")
+}
+
+func (err BindingError) fieldErrors() []FieldError {
+	fieldErrs := make([]FieldError, 0, len(err.Errors))
+	for _, parameterErr := range err.Errors {
+		fieldErrs = append(fieldErrs, FieldError{
+			Field:   parameterErr.Name,
+			Message: parameterErr.Err.Error(),
+		})
+	}
+
+	return fieldErrs
+}
+
+// the order defines the priority of the sources
+func newBindingSources(options bindingOptions) []bindingSource {
+	return []bindingSource{
+		{
+			tag: pathParameterSource,
+			getValues: func(request *http.Request, name string) ([]string, error) {
+				value, ok := options.pathParameterSource.PathParameter(request, name)
+				if !ok {
+					return nil, nil
+				}
+
+				return []string{value}, nil
+			},
+		},
+		{
+			tag: queryParameterSource,
+			getValues: func(request *http.Request, name string) ([]string, error) {
+				return request.URL.Query()[name], nil
+			},
+		},
+		{
+			tag: headerParameterSource,
+			getValues: func(request *http.Request, name string) ([]string, error) {
+				return request.Header[http.CanonicalHeaderKey(name)], nil
+			},
+		},
+		{
+			tag: "cookie",
+			getValues: func(request *http.Request, name string) ([]string, error) {
+				var values []string
+				for _, cookie := range request.Cookies() {
+					if cookie.Name == name {
+						values = append(values, cookie.Value)
+					}
+				}
+
+				return values, nil
+			},
+		},
+		{
+			tag: "form",
+			getValues: func(request *http.Request, name string) ([]string, error) {
+				if err := request.ParseForm(); err != nil {
+					err = BadRequest(err)
+					return nil, errors.Wrap(err, "unable to parse the form")
+				}
+
+				return request.PostForm[name], nil
+			},
+		},
+	}
+}
+
+func bindStructure(
+	request *http.Request,
+	sources []bindingSource,
+	structure reflect.Value,
+	bindingErr *BindingError,
+) error {
+	structureType := structure.Type()
+	for index := 0; index < structureType.NumField(); index++ {
+		field := structureType.Field(index)
+		fieldValue := structure.Field(index)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct &&
+			!hasBindingTags(field, sources) {
+			err := bindStructure(request, sources, fieldValue, bindingErr)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+		if field.PkgPath != "" {
+			// the field is unexported
+			continue
+		}
+
+		for _, source := range sources {
+			name, ok := field.Tag.Lookup(source.tag)
+			if !ok || name == "" || name == "-" {
+				continue
+			}
+
+			values, err := source.getValues(request, name)
+			if err != nil {
+				return err
+			}
+			if len(values) == 0 {
+				continue
+			}
+
+			err = parseParameterValues(values, fieldValue)
+			if errors.Is(err, ErrUnsupportedType) {
+				return errors.Wrapf(err, "unable to bind the %s field", field.Name)
+			}
+			if err != nil {
+				bindingErr.Errors = append(bindingErr.Errors, ParameterError{
+					Source: source.tag,
+					Name:   name,
+					Err:    err,
+				})
+			}
+
+			break
+		}
+	}
+
+	return nil
+}
+
+func hasBindingTags(field reflect.StructField, sources []bindingSource) bool {
+	for _, source := range sources {
+		if _, ok := field.Tag.Lookup(source.tag); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// it parses all values into a slice (except a byte slice);
+// otherwise, only the first value is used
+func parseParameterValues(values []string, target reflect.Value) error {
+	if !isParameterSlice(target.Type()) {
+		return parseParameterValue(values[0], target)
+	}
+
+	items := reflect.MakeSlice(target.Type(), len(values), len(values))
+	for index, value := range values {
+		if err := parseParameterValue(value, items.Index(index)); err != nil {
+			return errors.Wrapf(err, "unable to parse the value #%d", index)
+		}
+	}
+
+	target.Set(items)
+	return nil
+}
+
+func isParameterSlice(targetType reflect.Type) bool {
+	if targetType.Kind() != reflect.Slice ||
+		targetType.Elem().Kind() == reflect.Uint8 {
+		return false
+	}
+
+	// a slice type may have the registered parser or may implement
+	// the encoding.TextUnmarshaler interface itself
+	if _, ok := lookupParameterParser(targetType); ok {
+		return false
+	}
+
+	return !reflect.PtrTo(targetType).Implements(textUnmarshalerType)
+}
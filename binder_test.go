@@ -0,0 +1,265 @@
+package httputils
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type testBindingPagination struct {
+	Limit  int  `query:"limit"`
+	Offset *int `query:"offset"`
+}
+
+type testBindingRequest struct {
+	testBindingPagination
+
+	ID         uint64        `path:"id"`
+	Tenant     string        `header:"X-Tenant"`
+	Session    string        `cookie:"session"`
+	Name       string        `form:"name" query:"name"`
+	Tags       []string      `query:"tag"`
+	Enabled    bool          `query:"enabled"`
+	Ratio      float32       `query:"ratio"`
+	Since      time.Time     `query:"since"`
+	Timeout    time.Duration `header:"X-Timeout"`
+	Address    net.IP        `query:"address"`
+	Addresses  []net.IP      `query:"addresses"`
+	Ignored    string        `query:"-"`
+	unexported string        `query:"unexported"` // nolint: structcheck
+}
+
+func TestBindRequest(test *testing.T) {
+	newRequest := func(
+		pathParameters map[string]string,
+		query string,
+		form url.Values,
+	) *http.Request {
+		var request *http.Request
+		if form != nil {
+			request = httptest.NewRequest(
+				http.MethodPost,
+				"http://example.com/?"+query,
+				strings.NewReader(form.Encode()),
+			)
+			request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		} else {
+			request = httptest.NewRequest(
+				http.MethodGet,
+				"http://example.com/?"+query,
+				nil,
+			)
+		}
+
+		return mux.SetURLVars(request, pathParameters)
+	}
+
+	for _, data := range []struct {
+		name           string
+		request        *http.Request
+		data           interface{}
+		options        []BindingOption
+		wantData       interface{}
+		wantErr        string
+		wantStatusCode int
+		wantFieldErrs  []FieldError
+	}{
+		{
+			name: "success",
+			request: func() *http.Request {
+				request := newRequest(
+					map[string]string{"id": "23"},
+					"limit=10&offset=5&name=query&tag=one&tag=two&enabled=true"+
+						"&ratio=0.5&since=2006-01-02T15:04:05Z&address=127.0.0.1"+
+						"&addresses=10.0.0.1&addresses=10.0.0.2&unexported=test",
+					url.Values{"name": {"form"}},
+				)
+				request.Header.Set("X-Tenant", "tenant")
+				request.Header.Set("X-Timeout", "1m30s")
+				request.AddCookie(&http.Cookie{Name: "session", Value: "100500"})
+
+				return request
+			}(),
+			data: &testBindingRequest{Ignored: "default"},
+			wantData: &testBindingRequest{
+				testBindingPagination: testBindingPagination{
+					Limit:  10,
+					Offset: func() *int { offset := 5; return &offset }(),
+				},
+				ID:      23,
+				Tenant:  "tenant",
+				Session: "100500",
+				Name:    "query",
+				Tags:    []string{"one", "two"},
+				Enabled: true,
+				Ratio:   0.5,
+				Since:   time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC),
+				Timeout: 90 * time.Second,
+				Address: net.ParseIP("127.0.0.1"),
+				Addresses: []net.IP{
+					net.ParseIP("10.0.0.1"),
+					net.ParseIP("10.0.0.2"),
+				},
+				Ignored: "default",
+			},
+			wantErr: "",
+		},
+		{
+			name: "success with the form and defaults",
+			request: newRequest(
+				nil,
+				"",
+				url.Values{"name": {"form"}},
+			),
+			data: &testBindingRequest{
+				testBindingPagination: testBindingPagination{Limit: 100},
+			},
+			wantData: &testBindingRequest{
+				testBindingPagination: testBindingPagination{Limit: 100},
+				Name:                  "form",
+			},
+			wantErr: "",
+		},
+		{
+			name:    "success with the path parameter source",
+			request: newRequest(map[string]string{"id": "42"}, "", nil),
+			data:    &testBindingRequest{},
+			options: []BindingOption{
+				WithBindingPathParameterSource(MapPathParameterSource{"id": "23"}),
+			},
+			wantData: &testBindingRequest{ID: 23},
+			wantErr:  "",
+		},
+		{
+			name:     "error with the data",
+			request:  newRequest(nil, "", nil),
+			data:     &[]int{},
+			wantData: &[]int{},
+			wantErr: "the data is incorrect: " +
+				"it should be a non-nil pointer to a structure",
+			wantStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name:    "error with the unsupported type",
+			request: newRequest(nil, "data=test", nil),
+			data: &struct {
+				Data map[string]string `query:"data"`
+			}{},
+			wantData: &struct {
+				Data map[string]string `query:"data"`
+			}{},
+			wantErr: "unable to bind the Data field: " +
+				"the map[string]string type: the type is unsupported",
+			wantStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name: "error with the values",
+			request: func() *http.Request {
+				request := newRequest(
+					map[string]string{"id": "-1"},
+					"limit=10&offset=incorrect&tag=one&enabled=yes&since=2006"+
+						"&addresses=10.0.0.1&addresses=incorrect",
+					nil,
+				)
+				request.Header.Set("X-Timeout", "1")
+
+				return request
+			}(),
+			data: &testBindingRequest{},
+			wantData: &testBindingRequest{
+				testBindingPagination: testBindingPagination{Limit: 10},
+				Tags:                  []string{"one"},
+			},
+			wantErr: "unable to bind the request: " +
+				`query parameter "offset": strconv.ParseInt: ` +
+				`parsing "incorrect": invalid syntax; ` +
+				`path parameter "id": strconv.ParseUint: ` +
+				`parsing "-1": invalid syntax; ` +
+				`query parameter "enabled": strconv.ParseBool: ` +
+				`parsing "yes": invalid syntax; ` +
+				`query parameter "since": parsing time "2006" as ` +
+				`"2006-01-02T15:04:05Z07:00": cannot parse "" as "-"; ` +
+				`header parameter "X-Timeout": time: missing unit in duration "1"; ` +
+				`query parameter "addresses": unable to parse the value #1: ` +
+				`invalid IP address: incorrect`,
+			wantStatusCode: http.StatusBadRequest,
+			wantFieldErrs: []FieldError{
+				{
+					Field:   "offset",
+					Message: `strconv.ParseInt: parsing "incorrect": invalid syntax`,
+				},
+				{
+					Field:   "id",
+					Message: `strconv.ParseUint: parsing "-1": invalid syntax`,
+				},
+				{
+					Field:   "enabled",
+					Message: `strconv.ParseBool: parsing "yes": invalid syntax`,
+				},
+				{
+					Field: "since",
+					Message: `parsing time "2006" as "2006-01-02T15:04:05Z07:00": ` +
+						`cannot parse "" as "-"`,
+				},
+				{
+					Field:   "X-Timeout",
+					Message: `time: missing unit in duration "1"`,
+				},
+				{
+					Field: "addresses",
+					Message: "unable to parse the value #1: " +
+						"invalid IP address: incorrect",
+				},
+			},
+		},
+		{
+			name: "error with the form",
+			request: func() *http.Request {
+				request := httptest.NewRequest(
+					http.MethodPost,
+					"http://example.com/",
+					strings.NewReader("%"),
+				)
+				request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+				return request
+			}(),
+			data:           &testBindingRequest{},
+			wantData:       &testBindingRequest{},
+			wantErr:        `unable to parse the form: invalid URL escape "%"`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			gotErr := BindRequest(data.request, data.data, data.options...)
+
+			assert.Equal(test, data.wantData, data.data)
+			if data.wantErr == "" {
+				assert.NoError(test, gotErr)
+				return
+			}
+
+			assert.EqualError(test, gotErr, data.wantErr)
+			gotStatusCode := ResolveHTTPError(gotErr).StatusCode()
+			assert.Equal(test, data.wantStatusCode, gotStatusCode)
+			assert.Equal(test, data.wantFieldErrs, fieldErrors(gotErr))
+
+			var bindingErr BindingError
+			if errors.As(gotErr, &bindingErr) {
+				wantProblem := Problem{
+					Status:     http.StatusBadRequest,
+					Extensions: map[string]interface{}{"errors": data.wantFieldErrs},
+				}
+				assert.Equal(test, wantProblem, bindingErr.Problem())
+			}
+		})
+	}
+}
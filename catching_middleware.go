@@ -13,6 +13,9 @@ import (
 // of the http.ResponseWriter interface and logs it via the provided log.Logger
 // interface.
 //
+// The writer passed to the next handler supports the same optional interfaces
+// (http.Flusher, http.Hijacker, etc.) as the original one.
+//
 func CatchingMiddleware(logger log.Logger) mux.MiddlewareFunc {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(
@@ -20,7 +23,7 @@ func CatchingMiddleware(logger log.Logger) mux.MiddlewareFunc {
 			request *http.Request,
 		) {
 			catchingWriter := NewCatchingResponseWriter(writer)
-			next.ServeHTTP(catchingWriter, request)
+			next.ServeHTTP(catchingWriter.WithOptionalInterfaces(), request)
 
 			if err := catchingWriter.LastError(); err != nil {
 				logger.Logf("unable to write the HTTP response: %v", err)
@@ -83,6 +83,40 @@ func TestCatchingMiddleware(test *testing.T) {
 				request: httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
 			},
 		},
+		{
+			name: "success with optional interfaces",
+			args: args{
+				logger: new(MockLogger),
+			},
+			middlewareArgs: middlewareArgs{
+				next: func() http.Handler {
+					handler := new(MockHandler)
+					handler.
+						On(
+							"ServeHTTP",
+							mock.MatchedBy(func(writer http.ResponseWriter) bool {
+								writer.Write([]byte("test")) // nolint: errcheck
+
+								_, ok := writer.(http.Flusher)
+								return ok
+							}),
+							httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
+						).
+						Return()
+
+					return handler
+				}(),
+			},
+			handlerArgs: handlerArgs{
+				writer: func() http.ResponseWriter {
+					writer := new(MockExtendedResponseWriter)
+					writer.On("Write", []byte("test")).Return(4, nil)
+
+					return writer
+				}(),
+				request: httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
+			},
+		},
 		{
 			name: "error",
 			args: args{
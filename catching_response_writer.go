@@ -2,6 +2,7 @@ package httputils
 
 import (
 	"net/http"
+	"time"
 )
 
 // CatchingResponseWriter ...
@@ -12,14 +13,25 @@ import (
 // Errors of writing via the http.ResponseWriter interface are important
 // to handle. See for details: https://stackoverflow.com/a/43976633
 //
-// Attention! The CatchingResponseWriter structure only supports methods
-// that are declared directly in the http.ResponseWriter interface. Any optional
-// interfaces (http.Flusher, http.Hijacker, etc.) aren't supported.
+// Additionally, it records what was actually sent: the status code,
+// the number of written bytes, the time of the first written byte
+// and the number of superfluous calls of the WriteHeader() method.
+//
+// Attention! The CatchingResponseWriter structure itself only supports methods
+// that are declared directly in the http.ResponseWriter interface. To get
+// a writer that also supports the optional interfaces (http.Flusher,
+// http.Hijacker, etc.) of the wrapped http.ResponseWriter interface, use
+// the CatchingResponseWriter.WithOptionalInterfaces() method.
 //
 type CatchingResponseWriter struct {
 	http.ResponseWriter
 
-	lastError error
+	lastError                   error
+	statusCode                  int
+	headerWritten               bool
+	bytesWritten                int64
+	firstByteTime               time.Time
+	superfluousWriteHeaderCalls int
 }
 
 // NewCatchingResponseWriter ...
@@ -42,9 +54,105 @@ func (writer CatchingResponseWriter) LastError() error {
 	return writer.lastError
 }
 
+// StatusCode ...
+//
+// It returns the status code passed to the WriteHeader() method. If only
+// the Write() method was called, it returns http.StatusOK. If nothing
+// was written yet, it returns zero.
+//
+func (writer CatchingResponseWriter) StatusCode() int {
+	return writer.statusCode
+}
+
+// HeaderWritten ...
+//
+// It returns whether the header was already written, explicitly
+// via the WriteHeader() method or implicitly via the Write() method.
+//
+func (writer CatchingResponseWriter) HeaderWritten() bool {
+	return writer.headerWritten
+}
+
+// BytesWritten ...
+//
+// It returns the total number of bytes of the response body written
+// to the wrapped http.ResponseWriter interface.
+//
+func (writer CatchingResponseWriter) BytesWritten() int64 {
+	return writer.bytesWritten
+}
+
+// FirstByteTime ...
+//
+// It returns the time when the first byte of the response body was written.
+// If nothing was written yet, it returns the zero time.
+//
+func (writer CatchingResponseWriter) FirstByteTime() time.Time {
+	return writer.firstByteTime
+}
+
+// SuperfluousWriteHeaderCalls ...
+//
+// It returns the number of calls of the WriteHeader() method that occurred
+// after the header had already been written.
+//
+func (writer CatchingResponseWriter) SuperfluousWriteHeaderCalls() int {
+	return writer.superfluousWriteHeaderCalls
+}
+
+// Unwrap ...
+//
+// It returns the wrapped http.ResponseWriter interface. It's used
+// by the http.ResponseController structure.
+//
+func (writer CatchingResponseWriter) Unwrap() http.ResponseWriter {
+	return writer.ResponseWriter
+}
+
+// WriteHeader ...
+//
+// It passes the call to the wrapped http.ResponseWriter interface
+// and records the status code. Informational status codes (1xx) except
+// http.StatusSwitchingProtocols aren't recorded, because they can be
+// followed by the final header.
+//
+func (writer *CatchingResponseWriter) WriteHeader(statusCode int) {
+	switch {
+	case writer.headerWritten:
+		writer.superfluousWriteHeaderCalls++
+	case statusCode >= 100 && statusCode < 200 &&
+		statusCode != http.StatusSwitchingProtocols:
+		// informational status codes don't finish the header
+	default:
+		writer.statusCode = statusCode
+		writer.headerWritten = true
+	}
+
+	writer.ResponseWriter.WriteHeader(statusCode)
+}
+
 func (writer *CatchingResponseWriter) Write(p []byte) (n int, err error) {
+	writer.writeImplicitHeader()
+	if len(p) != 0 {
+		writer.markFirstByte()
+	}
+
 	n, err = writer.ResponseWriter.Write(p)
+	writer.bytesWritten += int64(n)
 	writer.lastError = err
 
 	return n, err
 }
+
+func (writer *CatchingResponseWriter) writeImplicitHeader() {
+	if !writer.headerWritten {
+		writer.statusCode = http.StatusOK
+		writer.headerWritten = true
+	}
+}
+
+func (writer *CatchingResponseWriter) markFirstByte() {
+	if writer.firstByteTime.IsZero() {
+		writer.firstByteTime = time.Now()
+	}
+}
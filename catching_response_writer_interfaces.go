@@ -0,0 +1,471 @@
+package httputils
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+)
+
+const (
+	flusherInterface = 1 << iota
+	hijackerInterface
+	pusherInterface
+	readerFromInterface
+	closeNotifierInterface
+)
+
+type flusherWithError interface {
+	http.Flusher
+
+	FlushError() error
+}
+
+type catchingFlusher CatchingResponseWriter
+
+func (writer *catchingFlusher) Flush() {
+	writer.FlushError() // nolint: errcheck
+}
+
+func (writer *catchingFlusher) FlushError() error {
+	// flushing sends the header if it wasn't sent yet
+	(*CatchingResponseWriter)(writer).writeImplicitHeader()
+
+	flusher, ok := writer.ResponseWriter.(interface{ FlushError() error })
+	if !ok {
+		// the error is unknown, so the last error should stay the same
+		writer.ResponseWriter.(http.Flusher).Flush()
+		return nil
+	}
+
+	err := flusher.FlushError()
+	writer.lastError = err
+
+	return err
+}
+
+type catchingHijacker CatchingResponseWriter
+
+func (writer *catchingHijacker) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return writer.ResponseWriter.(http.Hijacker).Hijack()
+}
+
+type catchingPusher CatchingResponseWriter
+
+func (writer *catchingPusher) Push(
+	target string,
+	options *http.PushOptions,
+) error {
+	return writer.ResponseWriter.(http.Pusher).Push(target, options)
+}
+
+type catchingReaderFrom CatchingResponseWriter
+
+func (writer *catchingReaderFrom) ReadFrom(reader io.Reader) (int64, error) {
+	(*CatchingResponseWriter)(writer).writeImplicitHeader()
+	(*CatchingResponseWriter)(writer).markFirstByte()
+
+	n, err := writer.ResponseWriter.(io.ReaderFrom).ReadFrom(reader)
+	writer.bytesWritten += n
+	writer.lastError = err
+
+	return n, err
+}
+
+type catchingCloseNotifier CatchingResponseWriter
+
+func (writer *catchingCloseNotifier) CloseNotify() <-chan bool {
+	// nolint: staticcheck
+	return writer.ResponseWriter.(http.CloseNotifier).CloseNotify()
+}
+
+// WithOptionalInterfaces ...
+//
+// It returns the http.ResponseWriter interface that is backed
+// by the CatchingResponseWriter object and supports exactly those optional
+// interfaces (http.Flusher, http.Hijacker, http.Pusher, io.ReaderFrom
+// and http.CloseNotifier) that are supported by the wrapped
+// http.ResponseWriter interface.
+//
+// Errors from the Flush() and ReadFrom() methods are saved the same way
+// as errors from the Write() method. The Flush() method saves an error only
+// if the wrapped http.ResponseWriter interface has the FlushError() method
+// (see the http.ResponseController structure); the returned writer provides
+// this method as well.
+//
+func (writer *CatchingResponseWriter) WithOptionalInterfaces() http.ResponseWriter {
+	var interfaces int
+	if _, ok := writer.ResponseWriter.(http.Flusher); ok {
+		interfaces |= flusherInterface
+	}
+	if _, ok := writer.ResponseWriter.(http.Hijacker); ok {
+		interfaces |= hijackerInterface
+	}
+	if _, ok := writer.ResponseWriter.(http.Pusher); ok {
+		interfaces |= pusherInterface
+	}
+	if _, ok := writer.ResponseWriter.(io.ReaderFrom); ok {
+		interfaces |= readerFromInterface
+	}
+	// nolint: staticcheck
+	if _, ok := writer.ResponseWriter.(http.CloseNotifier); ok {
+		interfaces |= closeNotifierInterface
+	}
+
+	switch interfaces {
+	case flusherInterface:
+		return struct {
+			*CatchingResponseWriter
+			flusherWithError
+		}{
+			writer,
+			(*catchingFlusher)(writer),
+		}
+	case hijackerInterface:
+		return struct {
+			*CatchingResponseWriter
+			http.Hijacker
+		}{
+			writer,
+			(*catchingHijacker)(writer),
+		}
+	case flusherInterface | hijackerInterface:
+		return struct {
+			*CatchingResponseWriter
+			flusherWithError
+			http.Hijacker
+		}{
+			writer,
+			(*catchingFlusher)(writer),
+			(*catchingHijacker)(writer),
+		}
+	case pusherInterface:
+		return struct {
+			*CatchingResponseWriter
+			http.Pusher
+		}{
+			writer,
+			(*catchingPusher)(writer),
+		}
+	case flusherInterface | pusherInterface:
+		return struct {
+			*CatchingResponseWriter
+			flusherWithError
+			http.Pusher
+		}{
+			writer,
+			(*catchingFlusher)(writer),
+			(*catchingPusher)(writer),
+		}
+	case hijackerInterface | pusherInterface:
+		return struct {
+			*CatchingResponseWriter
+			http.Hijacker
+			http.Pusher
+		}{
+			writer,
+			(*catchingHijacker)(writer),
+			(*catchingPusher)(writer),
+		}
+	case flusherInterface | hijackerInterface | pusherInterface:
+		return struct {
+			*CatchingResponseWriter
+			flusherWithError
+			http.Hijacker
+			http.Pusher
+		}{
+			writer,
+			(*catchingFlusher)(writer),
+			(*catchingHijacker)(writer),
+			(*catchingPusher)(writer),
+		}
+	case readerFromInterface:
+		return struct {
+			*CatchingResponseWriter
+			io.ReaderFrom
+		}{
+			writer,
+			(*catchingReaderFrom)(writer),
+		}
+	case flusherInterface | readerFromInterface:
+		return struct {
+			*CatchingResponseWriter
+			flusherWithError
+			io.ReaderFrom
+		}{
+			writer,
+			(*catchingFlusher)(writer),
+			(*catchingReaderFrom)(writer),
+		}
+	case hijackerInterface | readerFromInterface:
+		return struct {
+			*CatchingResponseWriter
+			http.Hijacker
+			io.ReaderFrom
+		}{
+			writer,
+			(*catchingHijacker)(writer),
+			(*catchingReaderFrom)(writer),
+		}
+	case flusherInterface | hijackerInterface | readerFromInterface:
+		return struct {
+			*CatchingResponseWriter
+			flusherWithError
+			http.Hijacker
+			io.ReaderFrom
+		}{
+			writer,
+			(*catchingFlusher)(writer),
+			(*catchingHijacker)(writer),
+			(*catchingReaderFrom)(writer),
+		}
+	case pusherInterface | readerFromInterface:
+		return struct {
+			*CatchingResponseWriter
+			http.Pusher
+			io.ReaderFrom
+		}{
+			writer,
+			(*catchingPusher)(writer),
+			(*catchingReaderFrom)(writer),
+		}
+	case flusherInterface | pusherInterface | readerFromInterface:
+		return struct {
+			*CatchingResponseWriter
+			flusherWithError
+			http.Pusher
+			io.ReaderFrom
+		}{
+			writer,
+			(*catchingFlusher)(writer),
+			(*catchingPusher)(writer),
+			(*catchingReaderFrom)(writer),
+		}
+	case hijackerInterface | pusherInterface | readerFromInterface:
+		return struct {
+			*CatchingResponseWriter
+			http.Hijacker
+			http.Pusher
+			io.ReaderFrom
+		}{
+			writer,
+			(*catchingHijacker)(writer),
+			(*catchingPusher)(writer),
+			(*catchingReaderFrom)(writer),
+		}
+	case flusherInterface | hijackerInterface | pusherInterface |
+		readerFromInterface:
+		return struct {
+			*CatchingResponseWriter
+			flusherWithError
+			http.Hijacker
+			http.Pusher
+			io.ReaderFrom
+		}{
+			writer,
+			(*catchingFlusher)(writer),
+			(*catchingHijacker)(writer),
+			(*catchingPusher)(writer),
+			(*catchingReaderFrom)(writer),
+		}
+	case closeNotifierInterface:
+		return struct {
+			*CatchingResponseWriter
+			http.CloseNotifier
+		}{
+			writer,
+			(*catchingCloseNotifier)(writer),
+		}
+	case flusherInterface | closeNotifierInterface:
+		return struct {
+			*CatchingResponseWriter
+			flusherWithError
+			http.CloseNotifier
+		}{
+			writer,
+			(*catchingFlusher)(writer),
+			(*catchingCloseNotifier)(writer),
+		}
+	case hijackerInterface | closeNotifierInterface:
+		return struct {
+			*CatchingResponseWriter
+			http.Hijacker
+			http.CloseNotifier
+		}{
+			writer,
+			(*catchingHijacker)(writer),
+			(*catchingCloseNotifier)(writer),
+		}
+	case flusherInterface | hijackerInterface | closeNotifierInterface:
+		return struct {
+			*CatchingResponseWriter
+			flusherWithError
+			http.Hijacker
+			http.CloseNotifier
+		}{
+			writer,
+			(*catchingFlusher)(writer),
+			(*catchingHijacker)(writer),
+			(*catchingCloseNotifier)(writer),
+		}
+	case pusherInterface | closeNotifierInterface:
+		return struct {
+			*CatchingResponseWriter
+			http.Pusher
+			http.CloseNotifier
+		}{
+			writer,
+			(*catchingPusher)(writer),
+			(*catchingCloseNotifier)(writer),
+		}
+	case flusherInterface | pusherInterface | closeNotifierInterface:
+		return struct {
+			*CatchingResponseWriter
+			flusherWithError
+			http.Pusher
+			http.CloseNotifier
+		}{
+			writer,
+			(*catchingFlusher)(writer),
+			(*catchingPusher)(writer),
+			(*catchingCloseNotifier)(writer),
+		}
+	case hijackerInterface | pusherInterface | closeNotifierInterface:
+		return struct {
+			*CatchingResponseWriter
+			http.Hijacker
+			http.Pusher
+			http.CloseNotifier
+		}{
+			writer,
+			(*catchingHijacker)(writer),
+			(*catchingPusher)(writer),
+			(*catchingCloseNotifier)(writer),
+		}
+	case flusherInterface | hijackerInterface | pusherInterface |
+		closeNotifierInterface:
+		return struct {
+			*CatchingResponseWriter
+			flusherWithError
+			http.Hijacker
+			http.Pusher
+			http.CloseNotifier
+		}{
+			writer,
+			(*catchingFlusher)(writer),
+			(*catchingHijacker)(writer),
+			(*catchingPusher)(writer),
+			(*catchingCloseNotifier)(writer),
+		}
+	case readerFromInterface | closeNotifierInterface:
+		return struct {
+			*CatchingResponseWriter
+			io.ReaderFrom
+			http.CloseNotifier
+		}{
+			writer,
+			(*catchingReaderFrom)(writer),
+			(*catchingCloseNotifier)(writer),
+		}
+	case flusherInterface | readerFromInterface | closeNotifierInterface:
+		return struct {
+			*CatchingResponseWriter
+			flusherWithError
+			io.ReaderFrom
+			http.CloseNotifier
+		}{
+			writer,
+			(*catchingFlusher)(writer),
+			(*catchingReaderFrom)(writer),
+			(*catchingCloseNotifier)(writer),
+		}
+	case hijackerInterface | readerFromInterface | closeNotifierInterface:
+		return struct {
+			*CatchingResponseWriter
+			http.Hijacker
+			io.ReaderFrom
+			http.CloseNotifier
+		}{
+			writer,
+			(*catchingHijacker)(writer),
+			(*catchingReaderFrom)(writer),
+			(*catchingCloseNotifier)(writer),
+		}
+	case flusherInterface | hijackerInterface | readerFromInterface |
+		closeNotifierInterface:
+		return struct {
+			*CatchingResponseWriter
+			flusherWithError
+			http.Hijacker
+			io.ReaderFrom
+			http.CloseNotifier
+		}{
+			writer,
+			(*catchingFlusher)(writer),
+			(*catchingHijacker)(writer),
+			(*catchingReaderFrom)(writer),
+			(*catchingCloseNotifier)(writer),
+		}
+	case pusherInterface | readerFromInterface | closeNotifierInterface:
+		return struct {
+			*CatchingResponseWriter
+			http.Pusher
+			io.ReaderFrom
+			http.CloseNotifier
+		}{
+			writer,
+			(*catchingPusher)(writer),
+			(*catchingReaderFrom)(writer),
+			(*catchingCloseNotifier)(writer),
+		}
+	case flusherInterface | pusherInterface | readerFromInterface |
+		closeNotifierInterface:
+		return struct {
+			*CatchingResponseWriter
+			flusherWithError
+			http.Pusher
+			io.ReaderFrom
+			http.CloseNotifier
+		}{
+			writer,
+			(*catchingFlusher)(writer),
+			(*catchingPusher)(writer),
+			(*catchingReaderFrom)(writer),
+			(*catchingCloseNotifier)(writer),
+		}
+	case hijackerInterface | pusherInterface | readerFromInterface |
+		closeNotifierInterface:
+		return struct {
+			*CatchingResponseWriter
+			http.Hijacker
+			http.Pusher
+			io.ReaderFrom
+			http.CloseNotifier
+		}{
+			writer,
+			(*catchingHijacker)(writer),
+			(*catchingPusher)(writer),
+			(*catchingReaderFrom)(writer),
+			(*catchingCloseNotifier)(writer),
+		}
+	case flusherInterface | hijackerInterface | pusherInterface |
+		readerFromInterface | closeNotifierInterface:
+		return struct {
+			*CatchingResponseWriter
+			flusherWithError
+			http.Hijacker
+			http.Pusher
+			io.ReaderFrom
+			http.CloseNotifier
+		}{
+			writer,
+			(*catchingFlusher)(writer),
+			(*catchingHijacker)(writer),
+			(*catchingPusher)(writer),
+			(*catchingReaderFrom)(writer),
+			(*catchingCloseNotifier)(writer),
+		}
+	}
+
+	return writer
+}
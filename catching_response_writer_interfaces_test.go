@@ -0,0 +1,319 @@
+package httputils
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCatchingResponseWriter_WithOptionalInterfaces(test *testing.T) {
+	type fields struct {
+		responseWriter http.ResponseWriter
+	}
+	type optionalInterfaces struct {
+		flusher       bool
+		hijacker      bool
+		pusher        bool
+		readerFrom    bool
+		closeNotifier bool
+	}
+
+	for _, data := range []struct {
+		name           string
+		fields         fields
+		wantInterfaces optionalInterfaces
+	}{
+		{
+			name: "without optional interfaces",
+			fields: fields{
+				responseWriter: new(MockResponseWriter),
+			},
+			wantInterfaces: optionalInterfaces{},
+		},
+		{
+			name: "with the http.Flusher interface",
+			fields: fields{
+				responseWriter: func() http.ResponseWriter {
+					writer := new(MockExtendedResponseWriter)
+					return struct {
+						http.ResponseWriter
+						http.Flusher
+					}{writer, writer}
+				}(),
+			},
+			wantInterfaces: optionalInterfaces{flusher: true},
+		},
+		{
+			name: "with the http.Hijacker and io.ReaderFrom interfaces",
+			fields: fields{
+				responseWriter: func() http.ResponseWriter {
+					writer := new(MockExtendedResponseWriter)
+					return struct {
+						http.ResponseWriter
+						http.Hijacker
+						io.ReaderFrom
+					}{writer, writer, writer}
+				}(),
+			},
+			wantInterfaces: optionalInterfaces{hijacker: true, readerFrom: true},
+		},
+		{
+			name: "with the http.Pusher and http.CloseNotifier interfaces",
+			fields: fields{
+				responseWriter: func() http.ResponseWriter {
+					writer := new(MockExtendedResponseWriter)
+					return struct {
+						http.ResponseWriter
+						http.Pusher
+						http.CloseNotifier // nolint: staticcheck
+					}{writer, writer, writer}
+				}(),
+			},
+			wantInterfaces: optionalInterfaces{pusher: true, closeNotifier: true},
+		},
+		{
+			name: "with all optional interfaces",
+			fields: fields{
+				responseWriter: new(MockExtendedResponseWriter),
+			},
+			wantInterfaces: optionalInterfaces{
+				flusher:       true,
+				hijacker:      true,
+				pusher:        true,
+				readerFrom:    true,
+				closeNotifier: true,
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			catchingWriter := NewCatchingResponseWriter(data.fields.responseWriter)
+			got := catchingWriter.WithOptionalInterfaces()
+
+			var gotInterfaces optionalInterfaces
+			_, gotInterfaces.flusher = got.(http.Flusher)
+			_, gotInterfaces.hijacker = got.(http.Hijacker)
+			_, gotInterfaces.pusher = got.(http.Pusher)
+			_, gotInterfaces.readerFrom = got.(io.ReaderFrom)
+			// nolint: staticcheck
+			_, gotInterfaces.closeNotifier = got.(http.CloseNotifier)
+
+			assert.Equal(test, data.wantInterfaces, gotInterfaces)
+			assert.Equal(
+				test,
+				data.fields.responseWriter,
+				got.(interface{ Unwrap() http.ResponseWriter }).Unwrap(),
+			)
+		})
+	}
+}
+
+func Test_catchingFlusher(test *testing.T) {
+	type fields struct {
+		responseWriter    *MockExtendedResponseWriter
+		withoutFlushError bool
+		lastError         error
+	}
+
+	for _, data := range []struct {
+		name        string
+		fields      fields
+		wantErr     assert.ErrorAssertionFunc
+		wantLastErr error
+	}{
+		{
+			name: "without the FlushError() method",
+			fields: fields{
+				responseWriter: func() *MockExtendedResponseWriter {
+					writer := new(MockExtendedResponseWriter)
+					writer.On("Flush").Return()
+
+					return writer
+				}(),
+				withoutFlushError: true,
+				lastError:         iotest.ErrTimeout,
+			},
+			wantErr:     assert.NoError,
+			wantLastErr: iotest.ErrTimeout,
+		},
+		{
+			name: "with the FlushError() method/success",
+			fields: fields{
+				responseWriter: func() *MockExtendedResponseWriter {
+					writer := new(MockExtendedResponseWriter)
+					writer.On("FlushError").Return(nil)
+
+					return writer
+				}(),
+				withoutFlushError: false,
+				lastError:         errors.New("dummy"),
+			},
+			wantErr:     assert.NoError,
+			wantLastErr: nil,
+		},
+		{
+			name: "with the FlushError() method/error",
+			fields: fields{
+				responseWriter: func() *MockExtendedResponseWriter {
+					writer := new(MockExtendedResponseWriter)
+					writer.On("FlushError").Return(iotest.ErrTimeout)
+
+					return writer
+				}(),
+				withoutFlushError: false,
+				lastError:         nil,
+			},
+			wantErr:     assert.Error,
+			wantLastErr: iotest.ErrTimeout,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			var responseWriter http.ResponseWriter = data.fields.responseWriter
+			if data.fields.withoutFlushError {
+				responseWriter = struct {
+					http.ResponseWriter
+					http.Flusher
+				}{data.fields.responseWriter, data.fields.responseWriter}
+			}
+
+			catchingWriter := &CatchingResponseWriter{
+				ResponseWriter: responseWriter,
+				lastError:      data.fields.lastError,
+			}
+			controller :=
+				http.NewResponseController(catchingWriter.WithOptionalInterfaces())
+			gotErr := controller.Flush()
+
+			mock.AssertExpectationsForObjects(test, data.fields.responseWriter)
+			data.wantErr(test, gotErr)
+			assert.Equal(test, data.wantLastErr, catchingWriter.lastError)
+			assert.Equal(test, http.StatusOK, catchingWriter.statusCode)
+			assert.True(test, catchingWriter.headerWritten)
+		})
+	}
+}
+
+func Test_catchingReaderFrom(test *testing.T) {
+	type fields struct {
+		responseWriter http.ResponseWriter
+		lastError      error
+	}
+	type args struct {
+		reader io.Reader
+	}
+
+	for _, data := range []struct {
+		name        string
+		fields      fields
+		args        args
+		wantN       int64
+		wantErr     assert.ErrorAssertionFunc
+		wantLastErr error
+	}{
+		{
+			name: "success",
+			fields: fields{
+				responseWriter: func() http.ResponseWriter {
+					writer := new(MockExtendedResponseWriter)
+					writer.On("ReadFrom", strings.NewReader("test")).Return(int64(4), nil)
+
+					return writer
+				}(),
+				lastError: errors.New("dummy"),
+			},
+			args:        args{strings.NewReader("test")},
+			wantN:       4,
+			wantErr:     assert.NoError,
+			wantLastErr: nil,
+		},
+		{
+			name: "error",
+			fields: fields{
+				responseWriter: func() http.ResponseWriter {
+					writer := new(MockExtendedResponseWriter)
+					writer.
+						On("ReadFrom", strings.NewReader("test")).
+						Return(int64(2), iotest.ErrTimeout)
+
+					return writer
+				}(),
+				lastError: nil,
+			},
+			args:        args{strings.NewReader("test")},
+			wantN:       2,
+			wantErr:     assert.Error,
+			wantLastErr: iotest.ErrTimeout,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			catchingWriter := &CatchingResponseWriter{
+				ResponseWriter: data.fields.responseWriter,
+				lastError:      data.fields.lastError,
+			}
+			gotN, gotErr := catchingWriter.WithOptionalInterfaces().(io.ReaderFrom).
+				ReadFrom(data.args.reader)
+
+			mock.AssertExpectationsForObjects(test, data.fields.responseWriter)
+			assert.Equal(test, data.wantN, gotN)
+			data.wantErr(test, gotErr)
+			assert.Equal(test, data.wantLastErr, catchingWriter.lastError)
+			assert.Equal(test, http.StatusOK, catchingWriter.statusCode)
+			assert.Equal(test, data.wantN, catchingWriter.bytesWritten)
+			assert.NotZero(test, catchingWriter.firstByteTime)
+		})
+	}
+}
+
+func Test_catchingHijacker(test *testing.T) {
+	conn, _ := net.Pipe()
+	readWriter := bufio.NewReadWriter(
+		bufio.NewReader(strings.NewReader("")),
+		bufio.NewWriter(io.Discard),
+	)
+	writer := new(MockExtendedResponseWriter)
+	writer.On("Hijack").Return(conn, readWriter, nil)
+
+	catchingWriter := NewCatchingResponseWriter(writer)
+	gotConn, gotReadWriter, gotErr :=
+		catchingWriter.WithOptionalInterfaces().(http.Hijacker).Hijack()
+
+	mock.AssertExpectationsForObjects(test, writer)
+	assert.Equal(test, conn, gotConn)
+	assert.Equal(test, readWriter, gotReadWriter)
+	assert.NoError(test, gotErr)
+}
+
+func Test_catchingPusher(test *testing.T) {
+	options := &http.PushOptions{Method: http.MethodGet}
+	writer := new(MockExtendedResponseWriter)
+	writer.On("Push", "/main.js", options).Return(http.ErrNotSupported)
+
+	catchingWriter := NewCatchingResponseWriter(writer)
+	gotErr := catchingWriter.WithOptionalInterfaces().(http.Pusher).
+		Push("/main.js", options)
+
+	mock.AssertExpectationsForObjects(test, writer)
+	assert.Equal(test, http.ErrNotSupported, gotErr)
+	assert.NoError(test, catchingWriter.lastError)
+}
+
+func Test_catchingCloseNotifier(test *testing.T) {
+	notifications := make(<-chan bool)
+	writer := new(MockExtendedResponseWriter)
+	writer.On("CloseNotify").Return(notifications)
+
+	catchingWriter := NewCatchingResponseWriter(writer)
+	// nolint: staticcheck
+	gotNotifications := catchingWriter.WithOptionalInterfaces().(http.CloseNotifier).
+		CloseNotify()
+
+	mock.AssertExpectationsForObjects(test, writer)
+	assert.Equal(test, notifications, gotNotifications)
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"testing"
 	"testing/iotest"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -74,25 +75,182 @@ func TestCatchingResponseWriter_LastError(test *testing.T) {
 	}
 }
 
+func TestCatchingResponseWriter_StatusCode(test *testing.T) {
+	writer := CatchingResponseWriter{statusCode: http.StatusNotFound}
+	got := writer.StatusCode()
+
+	assert.Equal(test, http.StatusNotFound, got)
+}
+
+func TestCatchingResponseWriter_HeaderWritten(test *testing.T) {
+	writer := CatchingResponseWriter{headerWritten: true}
+	got := writer.HeaderWritten()
+
+	assert.True(test, got)
+}
+
+func TestCatchingResponseWriter_BytesWritten(test *testing.T) {
+	writer := CatchingResponseWriter{bytesWritten: 23}
+	got := writer.BytesWritten()
+
+	assert.Equal(test, int64(23), got)
+}
+
+func TestCatchingResponseWriter_FirstByteTime(test *testing.T) {
+	firstByteTime := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+	writer := CatchingResponseWriter{firstByteTime: firstByteTime}
+	got := writer.FirstByteTime()
+
+	assert.Equal(test, firstByteTime, got)
+}
+
+func TestCatchingResponseWriter_SuperfluousWriteHeaderCalls(test *testing.T) {
+	writer := CatchingResponseWriter{superfluousWriteHeaderCalls: 2}
+	got := writer.SuperfluousWriteHeaderCalls()
+
+	assert.Equal(test, 2, got)
+}
+
+func TestCatchingResponseWriter_WriteHeader(test *testing.T) {
+	type fields struct {
+		responseWriter              http.ResponseWriter
+		statusCode                  int
+		headerWritten               bool
+		superfluousWriteHeaderCalls int
+	}
+	type args struct {
+		statusCode int
+	}
+
+	for _, data := range []struct {
+		name                            string
+		fields                          fields
+		args                            args
+		wantStatusCode                  int
+		wantHeaderWritten               bool
+		wantSuperfluousWriteHeaderCalls int
+	}{
+		{
+			name: "first call",
+			fields: fields{
+				responseWriter: func() http.ResponseWriter {
+					writer := new(MockResponseWriter)
+					writer.On("WriteHeader", http.StatusNotFound).Return()
+
+					return writer
+				}(),
+				statusCode:                  0,
+				headerWritten:               false,
+				superfluousWriteHeaderCalls: 0,
+			},
+			args:                            args{http.StatusNotFound},
+			wantStatusCode:                  http.StatusNotFound,
+			wantHeaderWritten:               true,
+			wantSuperfluousWriteHeaderCalls: 0,
+		},
+		{
+			name: "informational status code",
+			fields: fields{
+				responseWriter: func() http.ResponseWriter {
+					writer := new(MockResponseWriter)
+					writer.On("WriteHeader", http.StatusEarlyHints).Return()
+
+					return writer
+				}(),
+				statusCode:                  0,
+				headerWritten:               false,
+				superfluousWriteHeaderCalls: 0,
+			},
+			args:                            args{http.StatusEarlyHints},
+			wantStatusCode:                  0,
+			wantHeaderWritten:               false,
+			wantSuperfluousWriteHeaderCalls: 0,
+		},
+		{
+			name: "switching of protocols",
+			fields: fields{
+				responseWriter: func() http.ResponseWriter {
+					writer := new(MockResponseWriter)
+					writer.On("WriteHeader", http.StatusSwitchingProtocols).Return()
+
+					return writer
+				}(),
+				statusCode:                  0,
+				headerWritten:               false,
+				superfluousWriteHeaderCalls: 0,
+			},
+			args:                            args{http.StatusSwitchingProtocols},
+			wantStatusCode:                  http.StatusSwitchingProtocols,
+			wantHeaderWritten:               true,
+			wantSuperfluousWriteHeaderCalls: 0,
+		},
+		{
+			name: "superfluous call",
+			fields: fields{
+				responseWriter: func() http.ResponseWriter {
+					writer := new(MockResponseWriter)
+					writer.On("WriteHeader", http.StatusInternalServerError).Return()
+
+					return writer
+				}(),
+				statusCode:                  http.StatusOK,
+				headerWritten:               true,
+				superfluousWriteHeaderCalls: 1,
+			},
+			args:                            args{http.StatusInternalServerError},
+			wantStatusCode:                  http.StatusOK,
+			wantHeaderWritten:               true,
+			wantSuperfluousWriteHeaderCalls: 2,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			writer := &CatchingResponseWriter{
+				ResponseWriter:              data.fields.responseWriter,
+				statusCode:                  data.fields.statusCode,
+				headerWritten:               data.fields.headerWritten,
+				superfluousWriteHeaderCalls: data.fields.superfluousWriteHeaderCalls,
+			}
+			writer.WriteHeader(data.args.statusCode)
+
+			mock.AssertExpectationsForObjects(test, data.fields.responseWriter)
+			assert.Equal(test, data.wantStatusCode, writer.statusCode)
+			assert.Equal(test, data.wantHeaderWritten, writer.headerWritten)
+			assert.Equal(
+				test,
+				data.wantSuperfluousWriteHeaderCalls,
+				writer.superfluousWriteHeaderCalls,
+			)
+		})
+	}
+}
+
 func TestCatchingResponseWriter_Write(test *testing.T) {
 	type fields struct {
 		responseWriter http.ResponseWriter
 		lastError      error
+		statusCode     int
+		headerWritten  bool
+		bytesWritten   int64
+		firstByteTime  time.Time
 	}
 	type args struct {
 		p []byte
 	}
 
+	firstByteTime := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
 	for _, data := range []struct {
-		name        string
-		fields      fields
-		args        args
-		wantN       int
-		wantErr     assert.ErrorAssertionFunc
-		wantLastErr error
+		name              string
+		fields            fields
+		args              args
+		wantN             int
+		wantErr           assert.ErrorAssertionFunc
+		wantLastErr       error
+		wantStatusCode    int
+		wantBytesWritten  int64
+		wantFirstByteTime assert.ValueAssertionFunc
 	}{
 		{
-			name: "success",
+			name: "success/first call",
 			fields: fields{
 				responseWriter: func() http.ResponseWriter {
 					writer := new(MockResponseWriter)
@@ -100,12 +258,71 @@ func TestCatchingResponseWriter_Write(test *testing.T) {
 
 					return writer
 				}(),
-				lastError: nil,
+				lastError:     nil,
+				statusCode:    0,
+				headerWritten: false,
+				bytesWritten:  0,
+				firstByteTime: time.Time{},
+			},
+			args:              args{[]byte("test")},
+			wantN:             4,
+			wantErr:           assert.NoError,
+			wantLastErr:       nil,
+			wantStatusCode:    http.StatusOK,
+			wantBytesWritten:  4,
+			wantFirstByteTime: assert.NotZero,
+		},
+		{
+			name: "success/first call with an empty data",
+			fields: fields{
+				responseWriter: func() http.ResponseWriter {
+					writer := new(MockResponseWriter)
+					writer.On("Write", []byte{}).Return(0, nil)
+
+					return writer
+				}(),
+				lastError:     nil,
+				statusCode:    0,
+				headerWritten: false,
+				bytesWritten:  0,
+				firstByteTime: time.Time{},
+			},
+			args:              args{[]byte{}},
+			wantN:             0,
+			wantErr:           assert.NoError,
+			wantLastErr:       nil,
+			wantStatusCode:    http.StatusOK,
+			wantBytesWritten:  0,
+			wantFirstByteTime: assert.Zero,
+		},
+		{
+			name: "success/next call",
+			fields: fields{
+				responseWriter: func() http.ResponseWriter {
+					writer := new(MockResponseWriter)
+					writer.On("Write", []byte("test")).Return(4, nil)
+
+					return writer
+				}(),
+				lastError:     nil,
+				statusCode:    http.StatusCreated,
+				headerWritten: true,
+				bytesWritten:  23,
+				firstByteTime: firstByteTime,
+			},
+			args:             args{[]byte("test")},
+			wantN:            4,
+			wantErr:          assert.NoError,
+			wantLastErr:      nil,
+			wantStatusCode:   http.StatusCreated,
+			wantBytesWritten: 27,
+			wantFirstByteTime: func(
+				test assert.TestingT,
+				got interface{},
+				msgAndArgs ...interface{},
+			) bool {
+				return assert.Equal(test, firstByteTime, got, msgAndArgs...)
 			},
-			args:        args{[]byte("test")},
-			wantN:       4,
-			wantErr:     assert.NoError,
-			wantLastErr: nil,
 		},
 		{
 			name: "error (without the last error)",
@@ -116,12 +333,19 @@ func TestCatchingResponseWriter_Write(test *testing.T) {
 
 					return writer
 				}(),
-				lastError: nil,
+				lastError:     nil,
+				statusCode:    0,
+				headerWritten: false,
+				bytesWritten:  0,
+				firstByteTime: time.Time{},
 			},
-			args:        args{[]byte("test")},
-			wantN:       2,
-			wantErr:     assert.Error,
-			wantLastErr: iotest.ErrTimeout,
+			args:              args{[]byte("test")},
+			wantN:             2,
+			wantErr:           assert.Error,
+			wantLastErr:       iotest.ErrTimeout,
+			wantStatusCode:    http.StatusOK,
+			wantBytesWritten:  2,
+			wantFirstByteTime: assert.NotZero,
 		},
 		{
 			name: "error (with the last error)",
@@ -132,18 +356,29 @@ func TestCatchingResponseWriter_Write(test *testing.T) {
 
 					return writer
 				}(),
-				lastError: errors.New("dummy"),
+				lastError:     errors.New("dummy"),
+				statusCode:    0,
+				headerWritten: false,
+				bytesWritten:  0,
+				firstByteTime: time.Time{},
 			},
-			args:        args{[]byte("test")},
-			wantN:       2,
-			wantErr:     assert.Error,
-			wantLastErr: iotest.ErrTimeout,
+			args:              args{[]byte("test")},
+			wantN:             2,
+			wantErr:           assert.Error,
+			wantLastErr:       iotest.ErrTimeout,
+			wantStatusCode:    http.StatusOK,
+			wantBytesWritten:  2,
+			wantFirstByteTime: assert.NotZero,
 		},
 	} {
 		test.Run(data.name, func(test *testing.T) {
 			writer := &CatchingResponseWriter{
 				ResponseWriter: data.fields.responseWriter,
 				lastError:      data.fields.lastError,
+				statusCode:     data.fields.statusCode,
+				headerWritten:  data.fields.headerWritten,
+				bytesWritten:   data.fields.bytesWritten,
+				firstByteTime:  data.fields.firstByteTime,
 			}
 			gotN, gotErr := writer.Write(data.args.p)
 
@@ -151,6 +386,18 @@ func TestCatchingResponseWriter_Write(test *testing.T) {
 			assert.Equal(test, data.wantN, gotN)
 			data.wantErr(test, gotErr)
 			assert.Equal(test, data.wantLastErr, writer.lastError)
+			assert.Equal(test, data.wantStatusCode, writer.statusCode)
+			assert.True(test, writer.headerWritten)
+			assert.Equal(test, data.wantBytesWritten, writer.bytesWritten)
+			data.wantFirstByteTime(test, writer.firstByteTime)
 		})
 	}
 }
+
+func TestCatchingResponseWriter_Unwrap(test *testing.T) {
+	writer := new(MockResponseWriter)
+	got := NewCatchingResponseWriter(writer).Unwrap()
+
+	mock.AssertExpectationsForObjects(test, writer)
+	assert.Equal(test, writer, got)
+}
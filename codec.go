@@ -0,0 +1,282 @@
+package httputils
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrNotAcceptable ...
+//
+// It's returned (in a wrapped form) by the CodecRegistry.WriteBody() method
+// when none of the registered media types is acceptable for the client.
+// It's resolved to the http.StatusNotAcceptable status code (see
+// the ResolveHTTPError() function).
+//
+var ErrNotAcceptable = errors.New("the media types are not acceptable")
+
+// Codec ...
+//
+// It marshals and unmarshals data in some format (e.g. JSON or XML).
+//
+type Codec interface {
+	Marshal(data interface{}) ([]byte, error)
+	Unmarshal(bytes []byte, data interface{}) error
+}
+
+// CodecFuncs ...
+//
+// It's an adapter to use a pair of functions as the Codec interface.
+// It's compatible with the most of third-party packages, e.g.:
+//
+//	CodecFuncs{MarshalFunc: yaml.Marshal, UnmarshalFunc: yaml.Unmarshal}
+//
+type CodecFuncs struct {
+	MarshalFunc   func(data interface{}) ([]byte, error)
+	UnmarshalFunc func(bytes []byte, data interface{}) error
+}
+
+// JSONCodec ...
+//
+// It's the Codec interface for the JSON format.
+//
+var JSONCodec Codec = CodecFuncs{
+	MarshalFunc:   json.Marshal,
+	UnmarshalFunc: json.Unmarshal,
+}
+
+// XMLCodec ...
+//
+// It's the Codec interface for the XML format.
+//
+var XMLCodec Codec = CodecFuncs{
+	MarshalFunc:   xml.Marshal,
+	UnmarshalFunc: xml.Unmarshal,
+}
+
+// CodecRegistryOption ...
+//
+// It sets an optional parameter of the NewCodecRegistry() function.
+//
+type CodecRegistryOption func(registry *CodecRegistry)
+
+// CodecRegistry ...
+//
+// It holds codecs keyed by media types. It isn't safe for concurrent
+// registration, so codecs should be registered before serving requests.
+//
+type CodecRegistry struct {
+	mediaTypes  []string
+	codecs      map[string]Codec
+	maxBodySize int64
+}
+
+// WithMaxBodySize ...
+//
+// It sets the maximal size of the request body in bytes for
+// the CodecRegistry.ReadBody() method. By default, the size is unlimited.
+//
+func WithMaxBodySize(maxSize int64) CodecRegistryOption {
+	return func(registry *CodecRegistry) {
+		registry.maxBodySize = maxSize
+	}
+}
+
+// Marshal ...
+//
+// It calls the MarshalFunc function. It implements the Codec interface.
+//
+func (codec CodecFuncs) Marshal(data interface{}) ([]byte, error) {
+	return codec.MarshalFunc(data)
+}
+
+// Unmarshal ...
+//
+// It calls the UnmarshalFunc function. It implements the Codec interface.
+//
+func (codec CodecFuncs) Unmarshal(bytes []byte, data interface{}) error {
+	return codec.UnmarshalFunc(bytes, data)
+}
+
+// NewCodecRegistry ...
+//
+// It allocates and returns a new empty CodecRegistry object.
+//
+func NewCodecRegistry(options ...CodecRegistryOption) *CodecRegistry {
+	registry := &CodecRegistry{codecs: make(map[string]Codec)}
+	for _, option := range options {
+		option(registry)
+	}
+
+	return registry
+}
+
+// Register ...
+//
+// It registers the codec for the media type (e.g. "application/json").
+// A repeated registration replaces the codec.
+//
+// The order of the registration matters: the first registered media type
+// is used for clients without the Accept header, and ties of the content
+// negotiation are resolved in favour of the earlier registered media type.
+//
+func (registry *CodecRegistry) Register(mediaType string, codec Codec) {
+	mediaType = strings.ToLower(mediaType)
+	if _, ok := registry.codecs[mediaType]; !ok {
+		registry.mediaTypes = append(registry.mediaTypes, mediaType)
+	}
+
+	registry.codecs[mediaType] = codec
+}
+
+// ReadBody ...
+//
+// It reads the body of the request and then unmarshals it into the data
+// via the codec selected by the Content-Type header. The data should be
+// a non-nil pointer.
+//
+// If there's no codec for the media type itself, the codec for its structured
+// syntax suffix is used (e.g. the codec for "application/json"
+// for "application/merge-patch+json"). If there's no such codec either,
+// the ErrUnsupportedMediaType error is returned (in a wrapped form).
+//
+// If the maximal size is set (see the WithMaxBodySize() option), the body
+// is limited via the http.MaxBytesReader() function, and exceeding
+// of the limit is resolved to the http.StatusRequestEntityTooLarge status
+// code (see the ResolveHTTPError() function).
+//
+// Other errors of the reading and the unmarshalling are caused by the client,
+// so they are marked via the BadRequest() function. After reading, the body
+// is drained and closed like in the ReadJSONRequest() function.
+//
+func (registry *CodecRegistry) ReadBody(
+	request *http.Request,
+	data interface{},
+) error {
+	dataReflection := reflect.ValueOf(data)
+	if dataReflection.Kind() != reflect.Ptr || dataReflection.IsNil() {
+		return errors.New("the data is incorrect: it should be a non-nil pointer")
+	}
+
+	if request.Body == nil || request.Body == http.NoBody ||
+		request.ContentLength == 0 {
+		return errors.Wrap(BadRequest(ErrEmptyBody), "unable to read the request")
+	}
+	defer closeRequestBody(request.Body)
+
+	contentType := request.Header.Get("Content-Type")
+	codec, err := registry.lookupCodec(contentType)
+	if err != nil {
+		return errors.Wrap(err, "unable to read the request")
+	}
+
+	body := request.Body
+	if registry.maxBodySize > 0 {
+		// the method has no access to the writer, so the connection isn't closed
+		// after exceeding of the limit, but the reading is still stopped
+		body = http.MaxBytesReader(nil, body, registry.maxBodySize)
+	}
+
+	bytes, err := ioutil.ReadAll(body)
+	if err != nil {
+		err = errors.Wrap(newReadingError(err), "unable to read the data")
+		return errors.Wrap(err, "unable to read the request")
+	}
+	// the body may be empty despite the check above if its length is unknown
+	// (e.g. for a chunked body)
+	if len(bytes) == 0 {
+		return errors.Wrap(BadRequest(ErrEmptyBody), "unable to read the request")
+	}
+
+	if err := codec.Unmarshal(bytes, data); err != nil {
+		err = errors.Wrap(BadRequest(err), "unable to unmarshal the data")
+		return errors.Wrap(err, "unable to read the request")
+	}
+
+	return nil
+}
+
+// WriteBody ...
+//
+// It marshals the data via the codec selected by the Accept header
+// of the request (see the Register() method for details) and then writes it
+// in the writer. This function also sets the corresponding content type,
+// the Vary header and the specified status code.
+//
+// If none of the registered media types is acceptable, the ErrNotAcceptable
+// error is returned (in a wrapped form). In this case, as well as
+// if the data marshalling failed, neither the content type nor the status code
+// will be set.
+//
+func (registry *CodecRegistry) WriteBody(
+	writer http.ResponseWriter,
+	request *http.Request,
+	statusCode int,
+	data interface{},
+) error {
+	mediaType := negotiateMediaType(request.Header, registry.mediaTypes)
+	if mediaType == "" {
+		err := NewHTTPError(http.StatusNotAcceptable, ErrNotAcceptable)
+		return errors.Wrap(err, "unable to select the codec")
+	}
+
+	bytes, err := registry.codecs[mediaType].Marshal(data)
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal the data")
+	}
+
+	writer.Header().Add("Vary", "Accept")
+	writer.Header().Set("Content-Type", mediaType)
+	writer.WriteHeader(statusCode)
+	if _, err := writer.Write(bytes); err != nil {
+		return errors.Wrap(err, "unable to write the data")
+	}
+
+	return nil
+}
+
+func (registry *CodecRegistry) lookupCodec(
+	contentType string,
+) (Codec, error) {
+	if contentType == "" {
+		err := errors.Wrap(ErrUnsupportedMediaType, "the content type is missing")
+		return nil, NewHTTPError(http.StatusUnsupportedMediaType, err)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		err = errors.Wrapf(
+			ErrUnsupportedMediaType,
+			"the content type %q is incorrect: %v",
+			contentType,
+			err,
+		)
+		return nil, NewHTTPError(http.StatusUnsupportedMediaType, err)
+	}
+
+	if codec, ok := registry.codecs[mediaType]; ok {
+		return codec, nil
+	}
+
+	slashIndex := strings.IndexByte(mediaType, '/')
+	plusIndex := strings.LastIndexByte(mediaType, '+')
+	if plusIndex > slashIndex {
+		suffixMediaType := mediaType[:slashIndex+1] + mediaType[plusIndex+1:]
+		if codec, ok := registry.codecs[suffixMediaType]; ok {
+			return codec, nil
+		}
+	}
+
+	err = errors.Wrapf(
+		ErrUnsupportedMediaType,
+		"there's no codec for the content type %q",
+		mediaType,
+	)
+	return nil, NewHTTPError(http.StatusUnsupportedMediaType, err)
+}
@@ -0,0 +1,271 @@
+package httputils
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCodecData struct {
+	Number int `json:"number" xml:"number"`
+}
+
+func newTestCodecRegistry(options ...CodecRegistryOption) *CodecRegistry {
+	registry := NewCodecRegistry(options...)
+	registry.Register("application/json", JSONCodec)
+	registry.Register("Application/XML", XMLCodec)
+
+	return registry
+}
+
+func TestCodecRegistry_Register(test *testing.T) {
+	registry := NewCodecRegistry()
+	registry.Register("application/json", XMLCodec)
+	registry.Register("application/xml", XMLCodec)
+	registry.Register("application/json", JSONCodec)
+
+	wantMediaTypes := []string{"application/json", "application/xml"}
+	assert.Equal(test, wantMediaTypes, registry.mediaTypes)
+
+	gotBytes, err := registry.codecs["application/json"].Marshal(23)
+	assert.Equal(test, []byte("23"), gotBytes)
+	assert.NoError(test, err)
+}
+
+func TestCodecRegistry_ReadBody(test *testing.T) {
+	newRequest := func(contentType string, body io.Reader) *http.Request {
+		request := httptest.NewRequest(http.MethodPost, "http://example.com/", body)
+		if contentType != "" {
+			request.Header.Set("Content-Type", contentType)
+		}
+
+		return request
+	}
+
+	for _, data := range []struct {
+		name           string
+		options        []CodecRegistryOption
+		request        *http.Request
+		wantData       *testCodecData
+		wantErr        string
+		wantStatusCode int
+	}{
+		{
+			name: "success/JSON",
+			request: newRequest(
+				"application/json; charset=utf-8",
+				strings.NewReader(`{"number": 23}`),
+			),
+			wantData: &testCodecData{Number: 23},
+			wantErr:  "",
+		},
+		{
+			name: "success/XML",
+			request: newRequest(
+				"application/xml",
+				strings.NewReader(`<data><number>23</number></data>`),
+			),
+			wantData: &testCodecData{Number: 23},
+			wantErr:  "",
+		},
+		{
+			name: "success/structured syntax suffix",
+			request: newRequest(
+				"application/vnd.api+json",
+				strings.NewReader(`{"number": 23}`),
+			),
+			wantData: &testCodecData{Number: 23},
+			wantErr:  "",
+		},
+		{
+			name:    "success with the maximal body size",
+			options: []CodecRegistryOption{WithMaxBodySize(14)},
+			request: newRequest(
+				"application/json",
+				strings.NewReader(`{"number": 23}`),
+			),
+			wantData: &testCodecData{Number: 23},
+			wantErr:  "",
+		},
+		{
+			name: "error with the empty body of an unknown length",
+			request: func() *http.Request {
+				// an empty reader would be replaced with the http.NoBody variable
+				request := newRequest("application/json", nil)
+				request.Body = ioutil.NopCloser(strings.NewReader(""))
+				request.ContentLength = -1
+
+				return request
+			}(),
+			wantData:       &testCodecData{},
+			wantErr:        "unable to read the request: the body is empty",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "error with the empty body",
+			request:        newRequest("application/json", nil),
+			wantData:       &testCodecData{},
+			wantErr:        "unable to read the request: the body is empty",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:     "error with the missing content type",
+			request:  newRequest("", strings.NewReader(`{"number": 23}`)),
+			wantData: &testCodecData{},
+			wantErr: "unable to read the request: " +
+				"the content type is missing: the media type is unsupported",
+			wantStatusCode: http.StatusUnsupportedMediaType,
+		},
+		{
+			name: "error with the unsupported content type",
+			request: newRequest(
+				"application/yaml",
+				strings.NewReader("number: 23"),
+			),
+			wantData: &testCodecData{},
+			wantErr: "unable to read the request: " +
+				`there's no codec for the content type "application/yaml": ` +
+				"the media type is unsupported",
+			wantStatusCode: http.StatusUnsupportedMediaType,
+		},
+		{
+			name: "error with the data reading",
+			request: newRequest(
+				"application/json",
+				iotest.TimeoutReader(iotest.OneByteReader(strings.NewReader("{}"))),
+			),
+			wantData:       &testCodecData{},
+			wantErr:        "unable to read the request: unable to read the data: timeout",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:    "error with the exceeded maximal body size",
+			options: []CodecRegistryOption{WithMaxBodySize(13)},
+			request: newRequest(
+				"application/json",
+				strings.NewReader(`{"number": 23}`),
+			),
+			wantData: &testCodecData{},
+			wantErr: "unable to read the request: " +
+				"unable to read the data: the data is too large",
+			wantStatusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "error with the unmarshalling",
+			request: newRequest(
+				"application/json",
+				strings.NewReader("incorrect"),
+			),
+			wantData: &testCodecData{},
+			wantErr: "unable to read the request: unable to unmarshal the data: " +
+				"invalid character 'i' looking for beginning of value",
+			wantStatusCode: http.StatusBadRequest,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			registry := newTestCodecRegistry(data.options...)
+
+			gotData := new(testCodecData)
+			err := registry.ReadBody(data.request, gotData)
+
+			assert.Equal(test, data.wantData, gotData)
+			if data.wantErr == "" {
+				assert.NoError(test, err)
+				return
+			}
+
+			assert.EqualError(test, err, data.wantErr)
+			gotStatusCode := ResolveHTTPError(err).StatusCode()
+			assert.Equal(test, data.wantStatusCode, gotStatusCode)
+		})
+	}
+}
+
+func TestCodecRegistry_WriteBody(test *testing.T) {
+	for _, data := range []struct {
+		name           string
+		accept         string
+		data           interface{}
+		wantStatusCode int
+		wantHeader     http.Header
+		wantBody       string
+		wantErr        string
+		wantErrStatus  int
+	}{
+		{
+			name:           "success without the Accept header",
+			accept:         "",
+			data:           testCodecData{Number: 23},
+			wantStatusCode: http.StatusCreated,
+			wantHeader: http.Header{
+				"Content-Type": {"application/json"},
+				"Vary":         {"Accept"},
+			},
+			wantBody: `{"number":23}`,
+			wantErr:  "",
+		},
+		{
+			name:           "success with the quality values",
+			accept:         "application/json;q=0.5, application/*;q=0.9",
+			data:           testCodecData{Number: 23},
+			wantStatusCode: http.StatusCreated,
+			wantHeader: http.Header{
+				"Content-Type": {"application/xml"},
+				"Vary":         {"Accept"},
+			},
+			wantBody: `<testCodecData><number>23</number></testCodecData>`,
+			wantErr:  "",
+		},
+		{
+			name:           "error with the unacceptable media types",
+			accept:         "text/html",
+			data:           testCodecData{Number: 23},
+			wantStatusCode: http.StatusOK,
+			wantHeader:     http.Header{},
+			wantBody:       "",
+			wantErr: "unable to select the codec: " +
+				"the media types are not acceptable",
+			wantErrStatus: http.StatusNotAcceptable,
+		},
+		{
+			name:           "error with the marshalling",
+			accept:         "application/json",
+			data:           func() {},
+			wantStatusCode: http.StatusOK,
+			wantHeader:     http.Header{},
+			wantBody:       "",
+			wantErr: "unable to marshal the data: " +
+				"json: unsupported type: func()",
+			wantErrStatus: http.StatusInternalServerError,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+			if data.accept != "" {
+				request.Header.Set("Accept", data.accept)
+			}
+
+			recorder := httptest.NewRecorder()
+			err := newTestCodecRegistry().
+				WriteBody(recorder, request, http.StatusCreated, data.data)
+
+			assert.Equal(test, data.wantStatusCode, recorder.Code)
+			assert.Equal(test, data.wantHeader, recorder.Header())
+			assert.Equal(test, data.wantBody, recorder.Body.String())
+			if data.wantErr == "" {
+				assert.NoError(test, err)
+				return
+			}
+
+			assert.EqualError(test, err, data.wantErr)
+			gotStatusCode := ResolveHTTPError(err).StatusCode()
+			assert.Equal(test, data.wantErrStatus, gotStatusCode)
+		})
+	}
+}
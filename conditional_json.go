@@ -0,0 +1,147 @@
+package httputils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// WithVersion ...
+//
+// It sets the version of the data that is used as the strong ETag
+// (e.g. a revision number or a hash). The version is quoted automatically.
+// If it contains characters not allowed in an entity tag by RFC 7232
+// (e.g. quotes, backslashes, whitespaces or non-ASCII characters), a hash
+// of the version is used instead. See the WriteConditionalJSON() function.
+//
+func WithVersion(version string) WriteJSONOption {
+	return func(options *writeJSONOptions) {
+		options.version = version
+	}
+}
+
+// WithLastModified ...
+//
+// It sets the modification time of the data that is sent
+// as the Last-Modified header. See the WriteConditionalJSON() function.
+//
+func WithLastModified(lastModified time.Time) WriteJSONOption {
+	return func(options *writeJSONOptions) {
+		options.lastModified = lastModified
+	}
+}
+
+// WriteConditionalJSON ...
+//
+// It's an analog of the WriteJSONWithOptions() function that supports
+// conditional GET requests (see RFC 7232).
+//
+// It sends the strong ETag: the version set by the WithVersion() option
+// or, by default, a hash of the marshalled data. It also sends
+// the Last-Modified header if it's set by the WithLastModified() option.
+//
+// For the GET and HEAD requests with a successful status code, it evaluates
+// the If-None-Match header (or the If-Modified-Since header
+// if the former is absent). If the precondition fails, it responds
+// with the http.StatusNotModified status code without the body
+// and the content type, but with the validators and the extra headers.
+//
+func WriteConditionalJSON(
+	writer http.ResponseWriter,
+	request *http.Request,
+	statusCode int,
+	data interface{},
+	options ...WriteJSONOption,
+) error {
+	writeJSONOptions := newWriteJSONOptions(options)
+	dataBytes, err := encodeJSON(data, writeJSONOptions)
+	if err != nil {
+		return err
+	}
+
+	eTag := writeJSONOptions.strongETag()
+	if eTag == "" {
+		eTag = newHashETag(dataBytes)
+	}
+
+	setJSONHeaders(writer, writeJSONOptions, eTag)
+	if isNotModified(request, statusCode, eTag, writeJSONOptions.lastModified) {
+		writer.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	writer.Header().Set("Content-Type", writeJSONOptions.contentType)
+	writer.WriteHeader(statusCode)
+	if _, err := writer.Write(dataBytes); err != nil {
+		return errors.Wrap(err, "unable to write the data")
+	}
+
+	return nil
+}
+
+func isNotModified(
+	request *http.Request,
+	statusCode int,
+	eTag string,
+	lastModified time.Time,
+) bool {
+	if request.Method != http.MethodGet && request.Method != http.MethodHead {
+		return false
+	}
+	if statusCode < 200 || statusCode > 299 {
+		return false
+	}
+
+	if ifNoneMatch := request.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		return matchETag(ifNoneMatch, eTag)
+	}
+
+	ifModifiedSince := request.Header.Get("If-Modified-Since")
+	if ifModifiedSince == "" || lastModified.IsZero() {
+		return false
+	}
+
+	modifiedSince, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+
+	// the header has the second precision
+	return !lastModified.Truncate(time.Second).After(modifiedSince)
+}
+
+// it returns the strong ETag containing the SHA-256 hash of the data
+func newHashETag(data []byte) string {
+	hash := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(hash[:]) + `"`
+}
+
+// it checks that the value consists of the characters allowed in an entity
+// tag by RFC 7232 (except the obsolete non-ASCII ones)
+func isETagValue(value string) bool {
+	for index := 0; index < len(value); index++ {
+		character := value[index]
+		if character <= ' ' || character == '"' || character > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// it uses the weak comparison as required for the If-None-Match header
+func matchETag(eTags string, eTag string) bool {
+	opaqueTag := strings.TrimPrefix(eTag, "W/")
+	for _, candidate := range strings.Split(eTags, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == opaqueTag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,219 @@
+package httputils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteConditionalJSON(test *testing.T) {
+	type args struct {
+		request    *http.Request
+		statusCode int
+		data       interface{}
+		options    []WriteJSONOption
+	}
+
+	// SHA-256 of the `{"number":23}` string
+	const dataETag = `"2b9dd054768ad4c85b3a0f23b23e2a02` +
+		`86add7a9951ff45de52f491bfd14d89f"`
+	lastModified := time.Date(2006, time.January, 2, 15, 4, 5, 500, time.UTC)
+	newRequest := func(method string, header http.Header) *http.Request {
+		request := httptest.NewRequest(method, "http://example.com/", nil)
+		for key, values := range header {
+			request.Header[key] = values
+		}
+
+		return request
+	}
+
+	for _, data := range []struct {
+		name           string
+		args           args
+		wantStatusCode int
+		wantHeader     http.Header
+		wantBody       string
+		wantErr        assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/without preconditions",
+			args: args{
+				request:    newRequest(http.MethodGet, nil),
+				statusCode: http.StatusOK,
+				data:       map[string]int{"number": 23},
+				options:    []WriteJSONOption{WithHeader("Cache-Control", "no-cache")},
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeader: http.Header{
+				"Cache-Control": {"no-cache"},
+				"Content-Type":  {"application/json"},
+				"Etag":          {dataETag},
+			},
+			wantBody: `{"number":23}`,
+			wantErr:  assert.NoError,
+		},
+		{
+			name: "success/with the matched ETag",
+			args: args{
+				request: newRequest(http.MethodGet, http.Header{
+					"If-None-Match": {`"other", W/` + dataETag},
+				}),
+				statusCode: http.StatusOK,
+				data:       map[string]int{"number": 23},
+				options:    []WriteJSONOption{WithHeader("Cache-Control", "no-cache")},
+			},
+			wantStatusCode: http.StatusNotModified,
+			wantHeader: http.Header{
+				"Cache-Control": {"no-cache"},
+				"Etag":          {dataETag},
+			},
+			wantBody: "",
+			wantErr:  assert.NoError,
+		},
+		{
+			name: "success/with the unmatched ETag",
+			args: args{
+				request: newRequest(http.MethodGet, http.Header{
+					"If-None-Match":     {`"other"`},
+					"If-Modified-Since": {"Mon, 02 Jan 2006 15:04:05 GMT"},
+				}),
+				statusCode: http.StatusOK,
+				data:       map[string]int{"number": 23},
+				options: []WriteJSONOption{
+					WithVersion("v23"),
+					WithLastModified(lastModified),
+				},
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeader: http.Header{
+				"Content-Type":  {"application/json"},
+				"Etag":          {`"v23"`},
+				"Last-Modified": {"Mon, 02 Jan 2006 15:04:05 GMT"},
+			},
+			wantBody: `{"number":23}`,
+			wantErr:  assert.NoError,
+		},
+		{
+			name: "success/with the version",
+			args: args{
+				request: newRequest(http.MethodHead, http.Header{
+					"If-None-Match": {`"v23"`},
+				}),
+				statusCode: http.StatusOK,
+				data:       map[string]int{"number": 23},
+				options:    []WriteJSONOption{WithVersion("v23")},
+			},
+			wantStatusCode: http.StatusNotModified,
+			wantHeader:     http.Header{"Etag": {`"v23"`}},
+			wantBody:       "",
+			wantErr:        assert.NoError,
+		},
+		{
+			name: "success/with the version containing disallowed characters",
+			args: args{
+				request:    newRequest(http.MethodGet, nil),
+				statusCode: http.StatusOK,
+				data:       map[string]int{"number": 23},
+				options:    []WriteJSONOption{WithVersion("v\"23 \u00e9")},
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeader: http.Header{
+				"Content-Type": {"application/json"},
+				// SHA-256 of the version
+				"Etag": {
+					`"36c1f50b8d5ff132ad34377cdd8fb0c2` +
+						`1886638dc35e802a75011b8d2aeb180b"`,
+				},
+			},
+			wantBody: `{"number":23}`,
+			wantErr:  assert.NoError,
+		},
+		{
+			name: "success/with the unmodified data",
+			args: args{
+				request: newRequest(http.MethodGet, http.Header{
+					"If-Modified-Since": {"Mon, 02 Jan 2006 15:04:05 GMT"},
+				}),
+				statusCode: http.StatusOK,
+				data:       map[string]int{"number": 23},
+				options:    []WriteJSONOption{WithLastModified(lastModified)},
+			},
+			wantStatusCode: http.StatusNotModified,
+			wantHeader: http.Header{
+				"Etag":          {dataETag},
+				"Last-Modified": {"Mon, 02 Jan 2006 15:04:05 GMT"},
+			},
+			wantBody: "",
+			wantErr:  assert.NoError,
+		},
+		{
+			name: "success/with the modified data",
+			args: args{
+				request: newRequest(http.MethodGet, http.Header{
+					"If-Modified-Since": {"Mon, 02 Jan 2006 15:04:04 GMT"},
+				}),
+				statusCode: http.StatusOK,
+				data:       map[string]int{"number": 23},
+				options:    []WriteJSONOption{WithLastModified(lastModified)},
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeader: http.Header{
+				"Content-Type":  {"application/json"},
+				"Etag":          {dataETag},
+				"Last-Modified": {"Mon, 02 Jan 2006 15:04:05 GMT"},
+			},
+			wantBody: `{"number":23}`,
+			wantErr:  assert.NoError,
+		},
+		{
+			name: "success/with the unsafe method",
+			args: args{
+				request: newRequest(http.MethodPost, http.Header{
+					"If-None-Match": {"*"},
+				}),
+				statusCode: http.StatusCreated,
+				data:       map[string]int{"number": 23},
+				options:    nil,
+			},
+			wantStatusCode: http.StatusCreated,
+			wantHeader: http.Header{
+				"Content-Type": {"application/json"},
+				"Etag":         {dataETag},
+			},
+			wantBody: `{"number":23}`,
+			wantErr:  assert.NoError,
+		},
+		{
+			name: "error with the marshalling",
+			args: args{
+				request:    newRequest(http.MethodGet, nil),
+				statusCode: http.StatusOK,
+				data:       func() {},
+				options:    []WriteJSONOption{WithVersion("v23")},
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeader:     http.Header{},
+			wantBody:       "",
+			wantErr:        assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			recorder := httptest.NewRecorder()
+			gotErr := WriteConditionalJSON(
+				recorder,
+				data.args.request,
+				data.args.statusCode,
+				data.args.data,
+				data.args.options...,
+			)
+
+			assert.Equal(test, data.wantStatusCode, recorder.Code)
+			assert.Equal(test, data.wantHeader, recorder.Header())
+			assert.Equal(test, data.wantBody, recorder.Body.String())
+			data.wantErr(test, gotErr)
+		})
+	}
+}
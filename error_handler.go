@@ -0,0 +1,91 @@
+package httputils
+
+import (
+	"net/http"
+
+	"github.com/go-log/log"
+)
+
+// ErrorHandlerFunc ...
+//
+// It's an analog of the http.HandlerFunc type that returns an error instead
+// of writing it by itself. Use the ErrorHandler() function to convert it
+// to the http.Handler interface.
+//
+type ErrorHandlerFunc func(
+	writer http.ResponseWriter,
+	request *http.Request,
+) error
+
+// ErrorWriter ...
+//
+// It writes the error returned by the ErrorHandlerFunc handler. See
+// the WriteError(), WriteJSONError() and WriteProblemError() functions.
+//
+type ErrorWriter func(logger log.Logger, writer http.ResponseWriter, err error)
+
+// ErrorHandlerOption ...
+//
+// It sets an optional parameter of the ErrorHandler() function.
+//
+type ErrorHandlerOption func(options *errorHandlerOptions)
+
+type errorHandlerOptions struct {
+	errorWriter ErrorWriter
+}
+
+// WithErrorWriter ...
+//
+// It sets the writer of errors returned by the handler. By default,
+// the WriteError() function is used.
+//
+func WithErrorWriter(errorWriter ErrorWriter) ErrorHandlerOption {
+	return func(options *errorHandlerOptions) {
+		options.errorWriter = errorWriter
+	}
+}
+
+// ErrorHandler ...
+//
+// It converts the ErrorHandlerFunc handler to the http.Handler interface.
+//
+// An error returned by the handler is written via the ErrorWriter function
+// (typed errors are resolved by the ResolveHTTPError() function). If
+// the handler has already written the header, the error is only logged via
+// the provided log.Logger interface.
+//
+// Errors of writing via the http.ResponseWriter interface in the handler
+// are captured and logged the same way as in the CatchingMiddleware()
+// middleware.
+//
+func ErrorHandler(
+	handler ErrorHandlerFunc,
+	logger log.Logger,
+	options ...ErrorHandlerOption,
+) http.Handler {
+	errorHandlerOptions := errorHandlerOptions{errorWriter: WriteError}
+	for _, option := range options {
+		option(&errorHandlerOptions)
+	}
+
+	return http.HandlerFunc(func(
+		writer http.ResponseWriter,
+		request *http.Request,
+	) {
+		catchingWriter := NewCatchingResponseWriter(writer)
+		err := handler(catchingWriter.WithOptionalInterfaces(), request)
+		if writingErr := catchingWriter.LastError(); writingErr != nil {
+			logger.Logf("unable to write the HTTP response: %v", writingErr)
+		}
+		if err == nil {
+			return
+		}
+
+		if catchingWriter.HeaderWritten() {
+			logger.Logf("unable to handle the HTTP request: %v", err)
+			return
+		}
+
+		errorHandlerOptions.errorWriter(logger, writer, err)
+	})
+}
@@ -0,0 +1,222 @@
+package httputils
+
+import (
+	stdlog "log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"testing/iotest"
+
+	"github.com/go-log/log"
+	"github.com/go-log/log/print"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func ExampleErrorHandler() {
+	// use the standard logger for error handling
+	logger := stdlog.New(os.Stderr, "", stdlog.LstdFlags)
+	handler := ErrorHandler(
+		func(writer http.ResponseWriter, request *http.Request) error {
+			var id int
+			if err := ParsePathParameter(request, "id", &id); err != nil {
+				// the error will be written with the resolved status code
+				return errors.Wrap(err, "unable to get the ID")
+			}
+
+			return WriteJSON(writer, http.StatusOK, map[string]int{"id": id})
+		},
+		// wrap the standard logger via the github.com/go-log/log package
+		print.New(logger),
+		WithErrorWriter(WriteProblemError),
+	)
+
+	http.Handle("/", handler)
+	logger.Fatal(http.ListenAndServe(":8080", nil))
+}
+
+func TestErrorHandler(test *testing.T) {
+	type args struct {
+		handler ErrorHandlerFunc
+		logger  log.Logger
+		options []ErrorHandlerOption
+	}
+	type handlerArgs struct {
+		writer  http.ResponseWriter
+		request *http.Request
+	}
+
+	for _, data := range []struct {
+		name        string
+		args        args
+		handlerArgs handlerArgs
+		wantHeader  http.Header
+	}{
+		{
+			name: "success",
+			args: args{
+				handler: func(writer http.ResponseWriter, _ *http.Request) error {
+					_, err := writer.Write([]byte("test"))
+					return err
+				},
+				logger:  new(MockLogger),
+				options: nil,
+			},
+			handlerArgs: handlerArgs{
+				writer: func() http.ResponseWriter {
+					writer := new(MockResponseWriter)
+					writer.On("Write", []byte("test")).Return(4, nil)
+
+					return writer
+				}(),
+				request: httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
+			},
+			wantHeader: nil,
+		},
+		{
+			name: "error with the writing",
+			args: args{
+				handler: func(writer http.ResponseWriter, _ *http.Request) error {
+					writer.Write([]byte("test")) // nolint: errcheck
+					return nil
+				},
+				logger: func() log.Logger {
+					logger := new(MockLogger)
+					logger.
+						On(
+							"Logf",
+							"unable to write the HTTP response: %v",
+							iotest.ErrTimeout,
+						).
+						Return()
+
+					return logger
+				}(),
+				options: nil,
+			},
+			handlerArgs: handlerArgs{
+				writer: func() http.ResponseWriter {
+					writer := new(MockResponseWriter)
+					writer.On("Write", []byte("test")).Return(2, iotest.ErrTimeout)
+
+					return writer
+				}(),
+				request: httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
+			},
+			wantHeader: nil,
+		},
+		{
+			name: "error with the handling/with the default error writer",
+			args: args{
+				handler: func(http.ResponseWriter, *http.Request) error {
+					return errors.Wrap(NotFound(iotest.ErrTimeout), "dummy")
+				},
+				logger: func() log.Logger {
+					logger := new(MockLogger)
+					logger.On("Log", "dummy: timeout").Return()
+
+					return logger
+				}(),
+				options: nil,
+			},
+			handlerArgs: handlerArgs{
+				writer: func() http.ResponseWriter {
+					const body = "dummy: timeout\n"
+
+					writer := new(MockResponseWriter)
+					writer.On("Header").Return(http.Header{})
+					writer.On("WriteHeader", http.StatusNotFound).Return()
+					writer.On("Write", []byte(body)).Return(len(body), nil)
+
+					return writer
+				}(),
+				request: httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
+			},
+			wantHeader: http.Header{
+				"Content-Type":           {"text/plain; charset=utf-8"},
+				"X-Content-Type-Options": {"nosniff"},
+			},
+		},
+		{
+			name: "error with the handling/with the custom error writer",
+			args: args{
+				handler: func(http.ResponseWriter, *http.Request) error {
+					return errors.Wrap(NotFound(iotest.ErrTimeout), "dummy")
+				},
+				logger: func() log.Logger {
+					logger := new(MockLogger)
+					logger.On("Log", "dummy: timeout").Return()
+
+					return logger
+				}(),
+				options: []ErrorHandlerOption{WithErrorWriter(WriteJSONError)},
+			},
+			handlerArgs: handlerArgs{
+				writer: func() http.ResponseWriter {
+					const body = `{"error":"dummy: timeout"}`
+
+					writer := new(MockResponseWriter)
+					writer.On("Header").Return(http.Header{})
+					writer.On("WriteHeader", http.StatusNotFound).Return()
+					writer.On("Write", []byte(body)).Return(len(body), nil)
+
+					return writer
+				}(),
+				request: httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
+			},
+			wantHeader: http.Header{"Content-Type": {"application/json"}},
+		},
+		{
+			name: "error with the handling/after writing of the header",
+			args: args{
+				handler: func(writer http.ResponseWriter, _ *http.Request) error {
+					writer.WriteHeader(http.StatusOK)
+					return iotest.ErrTimeout
+				},
+				logger: func() log.Logger {
+					logger := new(MockLogger)
+					logger.
+						On(
+							"Logf",
+							"unable to handle the HTTP request: %v",
+							iotest.ErrTimeout,
+						).
+						Return()
+
+					return logger
+				}(),
+				options: nil,
+			},
+			handlerArgs: handlerArgs{
+				writer: func() http.ResponseWriter {
+					writer := new(MockResponseWriter)
+					writer.On("WriteHeader", http.StatusOK).Return()
+
+					return writer
+				}(),
+				request: httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
+			},
+			wantHeader: nil,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			handler := ErrorHandler(
+				data.args.handler,
+				data.args.logger,
+				data.args.options...,
+			)
+			handler.ServeHTTP(data.handlerArgs.writer, data.handlerArgs.request)
+
+			mock.AssertExpectationsForObjects(
+				test,
+				data.args.logger,
+				data.handlerArgs.writer,
+			)
+			if data.wantHeader != nil {
+				assert.Equal(test, data.wantHeader, data.handlerArgs.writer.Header())
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+module github.com/thewizardplusplus/go-http-utils
+
+go 1.22
+
+require (
+	github.com/AlekSi/pointer v1.1.0
+	github.com/go-log/log v0.2.0
+	github.com/golang/gddo v0.0.0-20200324184333-3c2cc9a6329d
+	github.com/gorilla/mux v1.7.4
+	github.com/pkg/errors v0.9.1
+	github.com/stretchr/testify v1.5.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/stretchr/objx v0.1.0 // indirect
+	gopkg.in/yaml.v2 v2.2.2 // indirect
+)
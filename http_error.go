@@ -0,0 +1,265 @@
+package httputils
+
+import (
+	"net/http"
+
+	"github.com/go-log/log"
+	"github.com/pkg/errors"
+)
+
+// HTTPError ...
+//
+// It wraps the cause error with the HTTP status code, the public message
+// and the optional code (a machine-readable identifier of the error).
+//
+// The public message is intended for clients. If it isn't set explicitly,
+// the message of the cause error is used for client errors (4xx) and
+// the status text is used for other errors, so that internal details
+// of server errors are not exposed.
+//
+type HTTPError struct {
+	statusCode int
+	message    string
+	code       string
+	cause      error
+}
+
+// NewHTTPError ...
+//
+// It allocates and returns a new HTTPError object with the specified status
+// code wrapping the provided cause error. The cause error may be nil.
+//
+func NewHTTPError(statusCode int, cause error) *HTTPError {
+	return &HTTPError{statusCode: statusCode, cause: cause}
+}
+
+// BadRequest ...
+//
+// It's a shortcut for the NewHTTPError() function
+// with the http.StatusBadRequest status code.
+//
+func BadRequest(cause error) *HTTPError {
+	return NewHTTPError(http.StatusBadRequest, cause)
+}
+
+// Unauthorized ...
+//
+// It's a shortcut for the NewHTTPError() function
+// with the http.StatusUnauthorized status code.
+//
+func Unauthorized(cause error) *HTTPError {
+	return NewHTTPError(http.StatusUnauthorized, cause)
+}
+
+// Forbidden ...
+//
+// It's a shortcut for the NewHTTPError() function
+// with the http.StatusForbidden status code.
+//
+func Forbidden(cause error) *HTTPError {
+	return NewHTTPError(http.StatusForbidden, cause)
+}
+
+// NotFound ...
+//
+// It's a shortcut for the NewHTTPError() function
+// with the http.StatusNotFound status code.
+//
+func NotFound(cause error) *HTTPError {
+	return NewHTTPError(http.StatusNotFound, cause)
+}
+
+// Conflict ...
+//
+// It's a shortcut for the NewHTTPError() function
+// with the http.StatusConflict status code.
+//
+func Conflict(cause error) *HTTPError {
+	return NewHTTPError(http.StatusConflict, cause)
+}
+
+// UnprocessableEntity ...
+//
+// It's a shortcut for the NewHTTPError() function
+// with the http.StatusUnprocessableEntity status code.
+//
+func UnprocessableEntity(cause error) *HTTPError {
+	return NewHTTPError(http.StatusUnprocessableEntity, cause)
+}
+
+// InternalServerError ...
+//
+// It's a shortcut for the NewHTTPError() function
+// with the http.StatusInternalServerError status code.
+//
+func InternalServerError(cause error) *HTTPError {
+	return NewHTTPError(http.StatusInternalServerError, cause)
+}
+
+// ResolveHTTPError ...
+//
+// It describes any error as the HTTPError object wrapping this error.
+//
+// The status code, the public message and the code are taken from the first
+// HTTPError object in the error chain (see the errors.As() function).
+// If there's no such object, the status code is taken from the first error
+// implementing the StatusCodeProvider interface. If there's no such error
+// either, the http.StatusInternalServerError status code is used.
+//
+// Since the resolved HTTPError object wraps the whole error, the default public
+// message of client errors contains the entire error chain.
+//
+func ResolveHTTPError(err error) *HTTPError {
+	return resolveHTTPError(err, http.StatusInternalServerError)
+}
+
+// WriteError ...
+//
+// It's an analog of the LoggingError() function that takes the status code
+// and the message from the error (see the ResolveHTTPError() function).
+// The full error is logged, but only the public message is written.
+//
+// An error of the writing is logged via the provided log.Logger interface too.
+//
+func WriteError(logger log.Logger, writer http.ResponseWriter, err error) {
+	logger.Log(err.Error())
+
+	httpErr := ResolveHTTPError(err)
+	catchingWriter := NewCatchingResponseWriter(writer)
+	http.Error(catchingWriter, httpErr.PublicMessage(), httpErr.StatusCode())
+	if err := catchingWriter.LastError(); err != nil {
+		logger.Logf("unable to write the error: %v", err)
+	}
+}
+
+// WriteJSONError ...
+//
+// It's an analog of the WriteError() function that writes the error as JSON:
+// an object with the "error" member containing the public message,
+// the optional "code" member containing the code and the optional "errors"
+// member containing problems with fields (see the ValidationError
+// and BindingError types).
+//
+func WriteJSONError(logger log.Logger, writer http.ResponseWriter, err error) {
+	logger.Log(err.Error())
+
+	httpErr := ResolveHTTPError(err)
+	err = WriteJSON(writer, httpErr.StatusCode(), newJSONError(httpErr))
+	if err != nil {
+		logger.Logf("unable to write the error: %v", err)
+	}
+}
+
+// StatusCode ...
+//
+// It returns the HTTP status code. It implements the StatusCodeProvider
+// interface.
+//
+func (err HTTPError) StatusCode() int {
+	return err.statusCode
+}
+
+// PublicMessage ...
+//
+// It returns the message intended for clients.
+//
+func (err HTTPError) PublicMessage() string {
+	switch {
+	case err.message != "":
+		return err.message
+	case err.statusCode < http.StatusInternalServerError && err.cause != nil:
+		return err.cause.Error()
+	default:
+		return http.StatusText(err.statusCode)
+	}
+}
+
+// Code ...
+//
+// It returns the optional code of the error.
+//
+func (err HTTPError) Code() string {
+	return err.code
+}
+
+// WithMessage ...
+//
+// It returns a copy of the HTTPError object with the specified public message.
+//
+func (err HTTPError) WithMessage(message string) *HTTPError {
+	err.message = message
+	return &err
+}
+
+// WithCode ...
+//
+// It returns a copy of the HTTPError object with the specified code.
+//
+func (err HTTPError) WithCode(code string) *HTTPError {
+	err.code = code
+	return &err
+}
+
+// Error ...
+//
+// It returns the message of the cause error prefixed by the explicitly set
+// public message (if any). If there's no cause error, it returns the public
+// message.
+//
+func (err HTTPError) Error() string {
+	switch {
+	case err.cause == nil:
+		return err.PublicMessage()
+	case err.message != "":
+		return err.message + ": " + err.cause.Error()
+	default:
+		return err.cause.Error()
+	}
+}
+
+// Cause ...
+//
+// It returns the cause error. It's used by the errors.Cause() function.
+//
+func (err HTTPError) Cause() error {
+	return err.cause
+}
+
+// Unwrap ...
+//
+// It returns the cause error. It's used by the errors.Is()
+// and errors.As() functions.
+//
+func (err HTTPError) Unwrap() error {
+	return err.cause
+}
+
+type jsonError struct {
+	Error  string       `json:"error"`
+	Code   string       `json:"code,omitempty"`
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+func newJSONError(httpErr *HTTPError) jsonError {
+	return jsonError{
+		Error:  httpErr.PublicMessage(),
+		Code:   httpErr.Code(),
+		Errors: fieldErrors(httpErr),
+	}
+}
+
+func resolveHTTPError(err error, defaultStatusCode int) *HTTPError {
+	resolvedErr := NewHTTPError(defaultStatusCode, err)
+
+	var httpErr *HTTPError
+	var statusCodeProvider StatusCodeProvider
+	if errors.As(err, &httpErr) {
+		resolvedErr.statusCode = httpErr.statusCode
+		resolvedErr.message = httpErr.message
+		resolvedErr.code = httpErr.code
+	} else if errors.As(err, &statusCodeProvider) {
+		resolvedErr.statusCode = statusCodeProvider.StatusCode()
+	}
+
+	return resolvedErr
+}
@@ -0,0 +1,462 @@
+package httputils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/AlekSi/pointer"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewHTTPError(test *testing.T) {
+	for _, data := range []struct {
+		name           string
+		constructor    func(cause error) *HTTPError
+		wantStatusCode int
+	}{
+		{
+			name: "NewHTTPError",
+			constructor: func(cause error) *HTTPError {
+				return NewHTTPError(http.StatusTeapot, cause)
+			},
+			wantStatusCode: http.StatusTeapot,
+		},
+		{
+			name:           "BadRequest",
+			constructor:    BadRequest,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "Unauthorized",
+			constructor:    Unauthorized,
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "Forbidden",
+			constructor:    Forbidden,
+			wantStatusCode: http.StatusForbidden,
+		},
+		{
+			name:           "NotFound",
+			constructor:    NotFound,
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "Conflict",
+			constructor:    Conflict,
+			wantStatusCode: http.StatusConflict,
+		},
+		{
+			name:           "UnprocessableEntity",
+			constructor:    UnprocessableEntity,
+			wantStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "InternalServerError",
+			constructor:    InternalServerError,
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := data.constructor(iotest.ErrTimeout)
+
+			wantErr := &HTTPError{
+				statusCode: data.wantStatusCode,
+				cause:      iotest.ErrTimeout,
+			}
+			assert.Equal(test, wantErr, got)
+		})
+	}
+}
+
+func TestResolveHTTPError(test *testing.T) {
+	type args struct {
+		err error
+	}
+
+	for _, data := range []struct {
+		name string
+		args args
+		want *HTTPError
+	}{
+		{
+			name: "with a plain error",
+			args: args{
+				err: iotest.ErrTimeout,
+			},
+			want: &HTTPError{
+				statusCode: http.StatusInternalServerError,
+				cause:      iotest.ErrTimeout,
+			},
+		},
+		{
+			name: "with the status code provider",
+			args: args{
+				err: testStatusCodeError{statusCode: http.StatusNotFound},
+			},
+			want: &HTTPError{
+				statusCode: http.StatusNotFound,
+				cause:      testStatusCodeError{statusCode: http.StatusNotFound},
+			},
+		},
+		{
+			name: "with the HTTP error",
+			args: args{
+				err: errors.Wrap(
+					Conflict(iotest.ErrTimeout).
+						WithMessage("already exists").
+						WithCode("already_exists"),
+					"dummy",
+				),
+			},
+			want: &HTTPError{
+				statusCode: http.StatusConflict,
+				message:    "already exists",
+				code:       "already_exists",
+				cause: errors.Wrap(
+					Conflict(iotest.ErrTimeout).
+						WithMessage("already exists").
+						WithCode("already_exists"),
+					"dummy",
+				),
+			},
+		},
+		{
+			name: "with the ReadJSON() function error",
+			args: args{
+				err: ReadJSON(strings.NewReader("incorrect"), new(int)),
+			},
+			want: &HTTPError{
+				statusCode: http.StatusBadRequest,
+				cause:      ReadJSON(strings.NewReader("incorrect"), new(int)),
+			},
+		},
+		{
+			name: "with the ParsePathParameter() function error",
+			args: args{
+				err: ParsePathParameter(
+					mux.SetURLVars(
+						httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
+						map[string]string{"test": "incorrect"},
+					),
+					"test",
+					pointer.ToInt(0),
+				),
+			},
+			want: &HTTPError{
+				statusCode: http.StatusBadRequest,
+				cause: ParsePathParameter(
+					mux.SetURLVars(
+						httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
+						map[string]string{"test": "incorrect"},
+					),
+					"test",
+					pointer.ToInt(0),
+				),
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := ResolveHTTPError(data.args.err)
+
+			assert.Equal(test, data.want.statusCode, got.statusCode)
+			assert.Equal(test, data.want.message, got.message)
+			assert.Equal(test, data.want.code, got.code)
+			assert.EqualError(test, got.cause, data.want.cause.Error())
+		})
+	}
+}
+
+func TestWriteError(test *testing.T) {
+	for _, data := range []struct {
+		name           string
+		err            error
+		writeErr       error
+		wantStatusCode int
+		wantBody       string
+	}{
+		{
+			name:           "success/client error",
+			err:            errors.Wrap(NotFound(iotest.ErrTimeout), "dummy"),
+			writeErr:       nil,
+			wantStatusCode: http.StatusNotFound,
+			wantBody:       "dummy: timeout\n",
+		},
+		{
+			name:           "success/server error",
+			err:            errors.Wrap(iotest.ErrTimeout, "dummy"),
+			writeErr:       nil,
+			wantStatusCode: http.StatusInternalServerError,
+			wantBody:       "Internal Server Error\n",
+		},
+		{
+			name:           "error",
+			err:            errors.Wrap(iotest.ErrTimeout, "dummy"),
+			writeErr:       iotest.ErrTimeout,
+			wantStatusCode: http.StatusInternalServerError,
+			wantBody:       "Internal Server Error\n",
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			logger := new(MockLogger)
+			logger.On("Log", "dummy: timeout").Return()
+			if data.writeErr != nil {
+				logger.
+					On("Logf", "unable to write the error: %v", data.writeErr).
+					Return()
+			}
+
+			writer := new(MockResponseWriter)
+			writer.On("Header").Return(http.Header{})
+			writer.On("WriteHeader", data.wantStatusCode).Return()
+			writer.
+				On("Write", []byte(data.wantBody)).
+				Return(len(data.wantBody), data.writeErr)
+
+			WriteError(logger, writer, data.err)
+
+			wantHeader := http.Header{
+				"Content-Type":           {"text/plain; charset=utf-8"},
+				"X-Content-Type-Options": {"nosniff"},
+			}
+			mock.AssertExpectationsForObjects(test, logger, writer)
+			assert.Equal(test, wantHeader, writer.Header())
+		})
+	}
+}
+
+func TestWriteJSONError(test *testing.T) {
+	for _, data := range []struct {
+		name           string
+		err            error
+		writeErr       error
+		wantStatusCode int
+		wantBody       string
+	}{
+		{
+			name: "success/client error",
+			err: errors.Wrap(
+				NotFound(iotest.ErrTimeout).WithCode("not_found"),
+				"dummy",
+			),
+			writeErr:       nil,
+			wantStatusCode: http.StatusNotFound,
+			wantBody:       `{"error":"dummy: timeout","code":"not_found"}`,
+		},
+		{
+			name: "success/validation error",
+			err: errors.Wrap(
+				ValidationError{
+					Errors: []FieldError{{Field: "name", Message: "is required"}},
+				},
+				"dummy",
+			),
+			writeErr:       nil,
+			wantStatusCode: http.StatusUnprocessableEntity,
+			wantBody: `{"error":"dummy: the data is invalid: name: is required",` +
+				`"errors":[{"field":"name","message":"is required"}]}`,
+		},
+		{
+			name: "success/validation error by pointer",
+			err: errors.Wrap(
+				&ValidationError{
+					Errors: []FieldError{{Field: "name", Message: "is required"}},
+				},
+				"dummy",
+			),
+			writeErr:       nil,
+			wantStatusCode: http.StatusUnprocessableEntity,
+			wantBody: `{"error":"dummy: the data is invalid: name: is required",` +
+				`"errors":[{"field":"name","message":"is required"}]}`,
+		},
+		{
+			name:           "success/server error",
+			err:            errors.Wrap(iotest.ErrTimeout, "dummy"),
+			writeErr:       nil,
+			wantStatusCode: http.StatusInternalServerError,
+			wantBody:       `{"error":"Internal Server Error"}`,
+		},
+		{
+			name:           "error",
+			err:            errors.Wrap(iotest.ErrTimeout, "dummy"),
+			writeErr:       iotest.ErrTimeout,
+			wantStatusCode: http.StatusInternalServerError,
+			wantBody:       `{"error":"Internal Server Error"}`,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			logger := new(MockLogger)
+			logger.On("Log", data.err.Error()).Return()
+			if data.writeErr != nil {
+				logger.
+					On(
+						"Logf",
+						"unable to write the error: %v",
+						mock.MatchedBy(func(err error) bool {
+							return errors.Cause(err) == data.writeErr
+						}),
+					).
+					Return()
+			}
+
+			writer := new(MockResponseWriter)
+			writer.On("Header").Return(http.Header{})
+			writer.On("WriteHeader", data.wantStatusCode).Return()
+			writer.
+				On("Write", []byte(data.wantBody)).
+				Return(len(data.wantBody), data.writeErr)
+
+			WriteJSONError(logger, writer, data.err)
+
+			wantHeader := http.Header{"Content-Type": {"application/json"}}
+			mock.AssertExpectationsForObjects(test, logger, writer)
+			assert.Equal(test, wantHeader, writer.Header())
+		})
+	}
+}
+
+func TestHTTPError_PublicMessage(test *testing.T) {
+	type fields struct {
+		statusCode int
+		message    string
+		cause      error
+	}
+
+	for _, data := range []struct {
+		name   string
+		fields fields
+		want   string
+	}{
+		{
+			name: "with the message",
+			fields: fields{
+				statusCode: http.StatusInternalServerError,
+				message:    "message",
+				cause:      iotest.ErrTimeout,
+			},
+			want: "message",
+		},
+		{
+			name: "client error",
+			fields: fields{
+				statusCode: http.StatusBadRequest,
+				message:    "",
+				cause:      iotest.ErrTimeout,
+			},
+			want: "timeout",
+		},
+		{
+			name: "client error without the cause",
+			fields: fields{
+				statusCode: http.StatusBadRequest,
+				message:    "",
+				cause:      nil,
+			},
+			want: "Bad Request",
+		},
+		{
+			name: "server error",
+			fields: fields{
+				statusCode: http.StatusInternalServerError,
+				message:    "",
+				cause:      iotest.ErrTimeout,
+			},
+			want: "Internal Server Error",
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			err := HTTPError{
+				statusCode: data.fields.statusCode,
+				message:    data.fields.message,
+				cause:      data.fields.cause,
+			}
+			got := err.PublicMessage()
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestHTTPError_Error(test *testing.T) {
+	type fields struct {
+		statusCode int
+		message    string
+		cause      error
+	}
+
+	for _, data := range []struct {
+		name   string
+		fields fields
+		want   string
+	}{
+		{
+			name: "without the cause",
+			fields: fields{
+				statusCode: http.StatusNotFound,
+				message:    "",
+				cause:      nil,
+			},
+			want: "Not Found",
+		},
+		{
+			name: "with the cause",
+			fields: fields{
+				statusCode: http.StatusNotFound,
+				message:    "",
+				cause:      iotest.ErrTimeout,
+			},
+			want: "timeout",
+		},
+		{
+			name: "with the cause and the message",
+			fields: fields{
+				statusCode: http.StatusNotFound,
+				message:    "message",
+				cause:      iotest.ErrTimeout,
+			},
+			want: "message: timeout",
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			err := HTTPError{
+				statusCode: data.fields.statusCode,
+				message:    data.fields.message,
+				cause:      data.fields.cause,
+			}
+			got := err.Error()
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestHTTPError_WithMessage(test *testing.T) {
+	err := NotFound(iotest.ErrTimeout)
+	got := err.WithMessage("message")
+
+	assert.Equal(test, NotFound(iotest.ErrTimeout), err)
+	assert.Equal(test, "message", got.message)
+	assert.Equal(test, "message", got.PublicMessage())
+}
+
+func TestHTTPError_WithCode(test *testing.T) {
+	err := NotFound(iotest.ErrTimeout)
+	got := err.WithCode("code")
+
+	assert.Equal(test, NotFound(iotest.ErrTimeout), err)
+	assert.Equal(test, "code", got.code)
+	assert.Equal(test, "code", got.Code())
+}
+
+func TestHTTPError_Unwrap(test *testing.T) {
+	err := errors.Wrap(NotFound(iotest.ErrTimeout), "dummy")
+
+	assert.True(test, errors.Is(err, iotest.ErrTimeout))
+	assert.Equal(test, iotest.ErrTimeout, errors.Cause(err))
+}
@@ -1,15 +1,205 @@
 package httputils
 
 import (
+	"bytes"
 	"encoding/json"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"reflect"
+	"strconv"
+	"time"
 
 	"github.com/pkg/errors"
 )
 
+// ErrDataTooLarge ...
+//
+// It's returned (in a wrapped form) by the ReadJSONWithOptions() function
+// when the data exceeds the maximal size. It's resolved
+// to the http.StatusRequestEntityTooLarge status code (see
+// the ResolveHTTPError() function).
+//
+var ErrDataTooLarge = errors.New("the data is too large")
+
+// PrettyPrintQueryParameter ...
+//
+// It's the query parameter that asks for the pretty-printing of JSON.
+// See the WithRequestedPrettyPrint() option.
+//
+const PrettyPrintQueryParameter = "pretty"
+
+// PrettyPrintHeader ...
+//
+// It's the header that asks for the pretty-printing of JSON.
+// See the WithRequestedPrettyPrint() option.
+//
+const PrettyPrintHeader = "X-Pretty-Print"
+
+const prettyPrintIndent = "  "
+
+// ReadJSONOption ...
+//
+// It sets an optional parameter of the ReadJSONWithOptions() function.
+//
+type ReadJSONOption func(options *readJSONOptions)
+
+type readJSONOptions struct {
+	maxSize               int64
+	disallowUnknownFields bool
+	allowTrailingData     bool
+	useNumber             bool
+	validator             ValidatorFunc
+}
+
+// WithMaxSize ...
+//
+// It sets the maximal size of the data in bytes. By default, the size
+// is unlimited.
+//
+func WithMaxSize(maxSize int64) ReadJSONOption {
+	return func(options *readJSONOptions) {
+		options.maxSize = maxSize
+	}
+}
+
+// WithDisallowedUnknownFields ...
+//
+// It makes the unmarshalling fail if an object contains keys that don't match
+// any non-ignored exported fields in the destination (see
+// the json.Decoder.DisallowUnknownFields() method).
+//
+func WithDisallowedUnknownFields() ReadJSONOption {
+	return func(options *readJSONOptions) {
+		options.disallowUnknownFields = true
+	}
+}
+
+// WithAllowedTrailingData ...
+//
+// It makes the unmarshalling ignore any data after the first JSON value.
+// By default, such data (except whitespaces) makes the unmarshalling fail
+// like in the ReadJSON() function.
+//
+func WithAllowedTrailingData() ReadJSONOption {
+	return func(options *readJSONOptions) {
+		options.allowTrailingData = true
+	}
+}
+
+// WithUseNumber ...
+//
+// It makes the unmarshalling of a number into an interface{} use
+// the json.Number type instead of the float64 type (see
+// the json.Decoder.UseNumber() method).
+//
+func WithUseNumber() ReadJSONOption {
+	return func(options *readJSONOptions) {
+		options.useNumber = true
+	}
+}
+
+// WithValidator ...
+//
+// It sets the function that validates the data after the unmarshalling
+// (in addition to the Validator interface).
+//
+func WithValidator(validator ValidatorFunc) ReadJSONOption {
+	return func(options *readJSONOptions) {
+		options.validator = validator
+	}
+}
+
+// WriteJSONOption ...
+//
+// It sets an optional parameter of the WriteJSONWithOptions() function.
+//
+type WriteJSONOption func(options *writeJSONOptions)
+
+type writeJSONOptions struct {
+	indent          string
+	escapeHTML      bool
+	contentType     string
+	trailingNewline bool
+	header          http.Header
+	version         string
+	lastModified    time.Time
+}
+
+// WithIndent ...
+//
+// It sets the indentation of nested elements (see
+// the json.Encoder.SetIndent() method). By default, the data isn't indented.
+//
+func WithIndent(indent string) WriteJSONOption {
+	return func(options *writeJSONOptions) {
+		options.indent = indent
+	}
+}
+
+// WithRequestedPrettyPrint ...
+//
+// It sets the indentation of nested elements with two spaces if the request
+// asks for it via the PrettyPrintQueryParameter query parameter
+// or the PrettyPrintHeader header. A value of the parameter and the header
+// may be omitted; otherwise, it should be true (see the strconv.ParseBool()
+// function).
+//
+func WithRequestedPrettyPrint(request *http.Request) WriteJSONOption {
+	return func(options *writeJSONOptions) {
+		if isPrettyPrintRequested(request) {
+			options.indent = prettyPrintIndent
+		}
+	}
+}
+
+// WithDisabledHTMLEscaping ...
+//
+// It disables escaping of the HTML characters <, > and & in strings (see
+// the json.Encoder.SetEscapeHTML() method).
+//
+func WithDisabledHTMLEscaping() WriteJSONOption {
+	return func(options *writeJSONOptions) {
+		options.escapeHTML = false
+	}
+}
+
+// WithContentType ...
+//
+// It sets the content type (e.g. "application/vnd.api+json"). By default,
+// it's "application/json".
+//
+func WithContentType(contentType string) WriteJSONOption {
+	return func(options *writeJSONOptions) {
+		options.contentType = contentType
+	}
+}
+
+// WithTrailingNewline ...
+//
+// It adds the newline after the data.
+//
+func WithTrailingNewline() WriteJSONOption {
+	return func(options *writeJSONOptions) {
+		options.trailingNewline = true
+	}
+}
+
+// WithHeader ...
+//
+// It adds the extra header. It may be used several times.
+// The Content-Type header should be set by the WithContentType() option.
+//
+func WithHeader(key string, value string) WriteJSONOption {
+	return func(options *writeJSONOptions) {
+		if options.header == nil {
+			options.header = http.Header{}
+		}
+
+		options.header.Add(key, value)
+	}
+}
+
 // ReadJSON ...
 //
 // It reads bytes from the reader and then unmarshals them into the data.
@@ -21,6 +211,9 @@ import (
 // If the data is not a non-nil pointer, this function will return an error,
 // and it will happen before the reader is read.
 //
+// Errors of the reading and the unmarshalling are caused by the client,
+// so they are marked via the BadRequest() function.
+//
 func ReadJSON(reader io.Reader, data interface{}) error {
 	dataReflection := reflect.ValueOf(data)
 	if dataReflection.Kind() != reflect.Ptr || dataReflection.IsNil() {
@@ -29,16 +222,90 @@ func ReadJSON(reader io.Reader, data interface{}) error {
 
 	bytes, err := ioutil.ReadAll(reader)
 	if err != nil {
-		return errors.Wrap(err, "unable to read the data")
+		return errors.Wrap(BadRequest(err), "unable to read the data")
 	}
 
 	if err := json.Unmarshal(bytes, data); err != nil {
-		return errors.Wrap(err, "unable to unmarshal the data")
+		return errors.Wrap(BadRequest(err), "unable to unmarshal the data")
 	}
 
 	return nil
 }
 
+// ReadJSONWithOptions ...
+//
+// It's an analog of the ReadJSON() function with optional parameters
+// (see the ReadJSONOption type). Like the ReadJSON() function, it rejects
+// any data (except whitespaces) after the first JSON value unless
+// the WithAllowedTrailingData() option is set.
+//
+// If the data exceeds the maximal size, this function will return
+// the ErrDataTooLarge error (in a wrapped form). The same happens
+// if the reader is created by the http.MaxBytesReader() function
+// and reports exceeding of its limit.
+//
+// After the unmarshalling, the data is validated: if it implements
+// the Validator interface, its Validate() method is called, and then
+// the function set by the WithValidator() option is called. An error
+// of the validation is returned as is if it's the ValidationError error
+// (by value or by pointer); otherwise, it's wrapped
+// by the UnprocessableEntity() function.
+//
+func ReadJSONWithOptions(
+	reader io.Reader,
+	data interface{},
+	options ...ReadJSONOption,
+) error {
+	var readJSONOptions readJSONOptions
+	for _, option := range options {
+		option(&readJSONOptions)
+	}
+
+	dataReflection := reflect.ValueOf(data)
+	if dataReflection.Kind() != reflect.Ptr || dataReflection.IsNil() {
+		return errors.New("the data is incorrect: it should be a non-nil pointer")
+	}
+
+	if readJSONOptions.maxSize > 0 {
+		// read one extra byte to detect exceeding of the limit
+		reader = io.LimitReader(reader, readJSONOptions.maxSize+1)
+	}
+
+	dataBytes, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return errors.Wrap(newReadingError(err), "unable to read the data")
+	}
+	if readJSONOptions.maxSize > 0 &&
+		int64(len(dataBytes)) > readJSONOptions.maxSize {
+		return errors.Wrap(newDataTooLargeError(), "unable to read the data")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(dataBytes))
+	if readJSONOptions.disallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	if readJSONOptions.useNumber {
+		decoder.UseNumber()
+	}
+
+	if err := decoder.Decode(data); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return errors.Wrap(BadRequest(err), "unable to unmarshal the data")
+	}
+
+	if !readJSONOptions.allowTrailingData {
+		if _, err := decoder.Token(); err != io.EOF {
+			err = errors.New("the data contains trailing data after the JSON value")
+			return errors.Wrap(BadRequest(err), "unable to unmarshal the data")
+		}
+	}
+
+	return validate(data, readJSONOptions.validator)
+}
+
 // WriteJSON ...
 //
 // It marshals the data and then writes it in the writer. This function
@@ -52,16 +319,156 @@ func WriteJSON(
 	statusCode int,
 	data interface{},
 ) error {
-	bytes, err := json.Marshal(data)
+	return WriteJSONWithOptions(writer, statusCode, data)
+}
+
+// WriteJSONWithOptions ...
+//
+// It's an analog of the WriteJSON() function with optional parameters
+// (see the WriteJSONOption type). Without the options, it writes the same
+// as the WriteJSON() function.
+//
+// The validators set by the WithVersion() and WithLastModified() options
+// are sent as the ETag and Last-Modified headers, but they aren't evaluated;
+// see the WriteConditionalJSON() function for that.
+//
+// If the data marshalling failed, nothing will be set, including the extra
+// headers.
+//
+func WriteJSONWithOptions(
+	writer http.ResponseWriter,
+	statusCode int,
+	data interface{},
+	options ...WriteJSONOption,
+) error {
+	writeJSONOptions := newWriteJSONOptions(options)
+	dataBytes, err := encodeJSON(data, writeJSONOptions)
 	if err != nil {
-		return errors.Wrap(err, "unable to marshal the data")
+		return err
 	}
 
-	writer.Header().Set("Content-Type", "application/json")
+	setJSONHeaders(writer, writeJSONOptions, writeJSONOptions.strongETag())
+	writer.Header().Set("Content-Type", writeJSONOptions.contentType)
 	writer.WriteHeader(statusCode)
-	if _, err := writer.Write(bytes); err != nil {
+	if _, err := writer.Write(dataBytes); err != nil {
 		return errors.Wrap(err, "unable to write the data")
 	}
 
 	return nil
 }
+
+func writeJSON(
+	writer http.ResponseWriter,
+	statusCode int,
+	contentType string,
+	data interface{},
+) error {
+	return WriteJSONWithOptions(
+		writer,
+		statusCode,
+		data,
+		WithContentType(contentType),
+	)
+}
+
+func newWriteJSONOptions(options []WriteJSONOption) writeJSONOptions {
+	writeJSONOptions := writeJSONOptions{
+		contentType: "application/json",
+		escapeHTML:  true,
+	}
+	for _, option := range options {
+		option(&writeJSONOptions)
+	}
+
+	return writeJSONOptions
+}
+
+func (options writeJSONOptions) strongETag() string {
+	if options.version == "" {
+		return ""
+	}
+
+	if !isETagValue(options.version) {
+		// the version can't be sent as is, but its hash identifies it as well
+		return newHashETag([]byte(options.version))
+	}
+
+	return `"` + options.version + `"`
+}
+
+func encodeJSON(data interface{}, options writeJSONOptions) ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(options.escapeHTML)
+	encoder.SetIndent("", options.indent)
+	if err := encoder.Encode(data); err != nil {
+		return nil, errors.Wrap(err, "unable to marshal the data")
+	}
+
+	dataBytes := buffer.Bytes()
+	if !options.trailingNewline {
+		// the json.Encoder.Encode() method always adds the newline
+		dataBytes = bytes.TrimSuffix(dataBytes, []byte("\n"))
+	}
+
+	return dataBytes, nil
+}
+
+// it sets the extra headers and the validators, but not the content type
+func setJSONHeaders(
+	writer http.ResponseWriter,
+	options writeJSONOptions,
+	eTag string,
+) {
+	header := writer.Header()
+	for key, values := range options.header {
+		for _, value := range values {
+			header.Add(key, value)
+		}
+	}
+	if eTag != "" {
+		header.Set("ETag", eTag)
+	}
+	if !options.lastModified.IsZero() {
+		lastModified := options.lastModified.UTC().Format(http.TimeFormat)
+		header.Set("Last-Modified", lastModified)
+	}
+}
+
+func isPrettyPrintRequested(request *http.Request) bool {
+	queryValues, ok := request.URL.Query()[PrettyPrintQueryParameter]
+	if ok && isEnabledFlag(queryValues[0]) {
+		return true
+	}
+
+	headerValues, ok := request.Header[http.CanonicalHeaderKey(PrettyPrintHeader)]
+	return ok && isEnabledFlag(headerValues[0])
+}
+
+func isEnabledFlag(value string) bool {
+	if value == "" {
+		return true
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	return err == nil && enabled
+}
+
+// it marks the error depending on whether the limit
+// of the http.MaxBytesReader() function was exceeded
+func newReadingError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return newDataTooLargeError()
+	}
+
+	return BadRequest(err)
+}
+
+func newDataTooLargeError() error {
+	return newTooLargeError(ErrDataTooLarge)
+}
+
+func newTooLargeError(err error) error {
+	return NewHTTPError(http.StatusRequestEntityTooLarge, err)
+}
@@ -0,0 +1,155 @@
+package httputils
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// it's the same limit that the http.Server structure uses
+// for discarding of an unread request body
+const maxDrainedBodySize = 256 << 10
+
+// ErrUnsupportedMediaType ...
+//
+// It's returned (in a wrapped form) by the ReadJSONRequest() function
+// and the CodecRegistry.ReadBody() method when the request has
+// an unsupported content type. It's resolved
+// to the http.StatusUnsupportedMediaType status code (see
+// the ResolveHTTPError() function).
+//
+var ErrUnsupportedMediaType = errors.New("the media type is unsupported")
+
+// ErrEmptyBody ...
+//
+// It's returned (in a wrapped form) by the ReadJSONRequest() function
+// and the CodecRegistry.ReadBody() method when the request has no body.
+// It's resolved to the http.StatusBadRequest status code (see
+// the ResolveHTTPError() function).
+//
+var ErrEmptyBody = errors.New("the body is empty")
+
+// ReadJSONRequest ...
+//
+// It's an analog of the ReadJSONWithOptions() function that reads the body
+// of the request.
+//
+// Before reading, it checks that the body isn't empty and that the content
+// type is application/json or has the +json suffix (e.g.
+// application/merge-patch+json). The only allowed charset is UTF-8.
+// The emptiness is checked by the Content-Length header and, if the length
+// is unknown (e.g. for a chunked body), by reading of the first byte.
+//
+// If the maximal size is set (see the WithMaxSize() option), the body
+// is limited via the http.MaxBytesReader() function, so the server will close
+// the connection after exceeding of the limit.
+//
+// After reading, the rest of the body is drained (within a reasonable limit)
+// and the body is closed, so the connection can be reused.
+//
+func ReadJSONRequest(
+	writer http.ResponseWriter,
+	request *http.Request,
+	data interface{},
+	options ...ReadJSONOption,
+) error {
+	if request.Body == nil || request.Body == http.NoBody ||
+		request.ContentLength == 0 {
+		return errors.Wrap(BadRequest(ErrEmptyBody), "unable to read the request")
+	}
+
+	body := request.Body
+	defer closeRequestBody(body)
+
+	contentType := request.Header.Get("Content-Type")
+	if err := checkJSONContentType(contentType); err != nil {
+		return errors.Wrap(err, "unable to read the request")
+	}
+
+	var readJSONOptions readJSONOptions
+	for _, option := range options {
+		option(&readJSONOptions)
+	}
+	if readJSONOptions.maxSize > 0 {
+		body = http.MaxBytesReader(writer, body, readJSONOptions.maxSize)
+	}
+
+	reader, err := peekRequestBody(body)
+	if err != nil {
+		return errors.Wrap(err, "unable to read the request")
+	}
+
+	if err := ReadJSONWithOptions(reader, data, options...); err != nil {
+		return errors.Wrap(err, "unable to read the request")
+	}
+
+	return nil
+}
+
+// it reads the first byte of the body to detect an empty body of an unknown
+// length and then returns the reader of the whole body
+func peekRequestBody(body io.Reader) (io.Reader, error) {
+	firstByte := make([]byte, 1)
+	if _, err := io.ReadFull(body, firstByte); err != nil {
+		if err == io.EOF {
+			return nil, BadRequest(ErrEmptyBody)
+		}
+
+		return nil, errors.Wrap(newReadingError(err), "unable to read the data")
+	}
+
+	return io.MultiReader(bytes.NewReader(firstByte), body), nil
+}
+
+// it drains the rest of the body (within a reasonable limit)
+// and closes it, so the connection can be reused
+func closeRequestBody(body io.ReadCloser) {
+	drainedBody := io.LimitReader(body, maxDrainedBodySize)
+	io.Copy(ioutil.Discard, drainedBody) // nolint: errcheck
+	body.Close()                         // nolint: errcheck
+}
+
+func checkJSONContentType(contentType string) error {
+	if contentType == "" {
+		err := errors.Wrap(ErrUnsupportedMediaType, "the content type is missing")
+		return NewHTTPError(http.StatusUnsupportedMediaType, err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		err = errors.Wrapf(
+			ErrUnsupportedMediaType,
+			"the content type %q is incorrect: %v",
+			contentType,
+			err,
+		)
+		return NewHTTPError(http.StatusUnsupportedMediaType, err)
+	}
+
+	if mediaType != "application/json" &&
+		!strings.HasSuffix(mediaType, "+json") {
+		err := errors.Wrapf(
+			ErrUnsupportedMediaType,
+			"the content type %q isn't JSON",
+			mediaType,
+		)
+		return NewHTTPError(http.StatusUnsupportedMediaType, err)
+	}
+
+	charset, ok := params["charset"]
+	if ok && !strings.EqualFold(charset, "utf-8") {
+		err := errors.Wrapf(
+			ErrUnsupportedMediaType,
+			"the charset %q isn't supported",
+			charset,
+		)
+		return NewHTTPError(http.StatusUnsupportedMediaType, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,248 @@
+package httputils
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type testRequestBody struct {
+	*strings.Reader
+
+	closed bool
+}
+
+func (body *testRequestBody) Close() error {
+	body.closed = true
+	return nil
+}
+
+func ExampleReadJSONRequest() {
+	http.HandleFunc("/", func(writer http.ResponseWriter, request *http.Request) {
+		var data struct {
+			Name string `json:"name"`
+		}
+		err := ReadJSONRequest(
+			writer,
+			request,
+			&data,
+			WithMaxSize(1<<20),
+			WithDisallowedUnknownFields(),
+		)
+		if err != nil {
+			httpErr := ResolveHTTPError(err)
+			http.Error(writer, httpErr.PublicMessage(), httpErr.StatusCode())
+
+			return
+		}
+
+		// use the data
+	})
+}
+
+func TestReadJSONRequest(test *testing.T) {
+	type testData struct {
+		FieldOne int
+		FieldTwo string
+	}
+	type args struct {
+		contentType string
+		body        string
+		options     []ReadJSONOption
+	}
+
+	for _, data := range []struct {
+		name           string
+		args           args
+		withoutBody    bool
+		unknownLength  bool
+		wantData       *testData
+		wantErr        error
+		wantStatusCode int
+	}{
+		{
+			name: "success",
+			args: args{
+				contentType: "application/json",
+				body:        `{"FieldOne": 23, "FieldTwo": "test"}`,
+				options:     nil,
+			},
+			wantData:       &testData{FieldOne: 23, FieldTwo: "test"},
+			wantErr:        nil,
+			wantStatusCode: 0,
+		},
+		{
+			name: "success with a body of an unknown length",
+			args: args{
+				contentType: "application/json",
+				body:        `{"FieldOne": 23, "FieldTwo": "test"}`,
+				options:     []ReadJSONOption{WithMaxSize(36)},
+			},
+			unknownLength:  true,
+			wantData:       &testData{FieldOne: 23, FieldTwo: "test"},
+			wantErr:        nil,
+			wantStatusCode: 0,
+		},
+		{
+			name: "success with a suffix and a charset",
+			args: args{
+				contentType: `application/merge-patch+json; charset="UTF-8"`,
+				body:        `{"FieldOne": 23, "FieldTwo": "test"}`,
+				options:     nil,
+			},
+			wantData:       &testData{FieldOne: 23, FieldTwo: "test"},
+			wantErr:        nil,
+			wantStatusCode: 0,
+		},
+		{
+			name: "error with an empty body",
+			args: args{
+				contentType: "application/json",
+				body:        "",
+				options:     nil,
+			},
+			wantData:       new(testData),
+			wantErr:        ErrEmptyBody,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "error with a missing body",
+			args: args{
+				contentType: "application/json",
+				body:        "",
+				options:     nil,
+			},
+			withoutBody:    true,
+			wantData:       new(testData),
+			wantErr:        ErrEmptyBody,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "error with an empty body of an unknown length",
+			args: args{
+				contentType: "application/json",
+				body:        "",
+				options:     nil,
+			},
+			unknownLength:  true,
+			wantData:       new(testData),
+			wantErr:        ErrEmptyBody,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "error with a missing content type",
+			args: args{
+				contentType: "",
+				body:        `{"FieldOne": 23, "FieldTwo": "test"}`,
+				options:     nil,
+			},
+			wantData:       new(testData),
+			wantErr:        ErrUnsupportedMediaType,
+			wantStatusCode: http.StatusUnsupportedMediaType,
+		},
+		{
+			name: "error with an incorrect content type",
+			args: args{
+				contentType: "application/json; charset",
+				body:        `{"FieldOne": 23, "FieldTwo": "test"}`,
+				options:     nil,
+			},
+			wantData:       new(testData),
+			wantErr:        ErrUnsupportedMediaType,
+			wantStatusCode: http.StatusUnsupportedMediaType,
+		},
+		{
+			name: "error with a non-JSON content type",
+			args: args{
+				contentType: "text/plain",
+				body:        `{"FieldOne": 23, "FieldTwo": "test"}`,
+				options:     nil,
+			},
+			wantData:       new(testData),
+			wantErr:        ErrUnsupportedMediaType,
+			wantStatusCode: http.StatusUnsupportedMediaType,
+		},
+		{
+			name: "error with an unsupported charset",
+			args: args{
+				contentType: "application/json; charset=windows-1251",
+				body:        `{"FieldOne": 23, "FieldTwo": "test"}`,
+				options:     nil,
+			},
+			wantData:       new(testData),
+			wantErr:        ErrUnsupportedMediaType,
+			wantStatusCode: http.StatusUnsupportedMediaType,
+		},
+		{
+			name: "error with the maximal size",
+			args: args{
+				contentType: "application/json",
+				body:        `{"FieldOne": 23, "FieldTwo": "test"}`,
+				options:     []ReadJSONOption{WithMaxSize(10)},
+			},
+			wantData:       new(testData),
+			wantErr:        ErrDataTooLarge,
+			wantStatusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "error with the unmarshalling",
+			args: args{
+				contentType: "application/json",
+				body:        "incorrect",
+				options:     nil,
+			},
+			wantData:       new(testData),
+			wantErr:        nil,
+			wantStatusCode: http.StatusBadRequest,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			body := &testRequestBody{Reader: strings.NewReader(data.args.body)}
+			request := httptest.NewRequest(http.MethodPost, "http://example.com/", nil)
+			if !data.withoutBody {
+				request.Body = body
+				request.ContentLength = int64(len(data.args.body))
+			}
+			if data.unknownLength {
+				request.ContentLength = -1
+			}
+			if data.args.contentType != "" {
+				request.Header.Set("Content-Type", data.args.contentType)
+			}
+
+			gotData := new(testData)
+			gotErr := ReadJSONRequest(
+				httptest.NewRecorder(),
+				request,
+				gotData,
+				data.args.options...,
+			)
+
+			assert.Equal(test, data.wantData, gotData)
+			if data.wantStatusCode == 0 {
+				assert.NoError(test, gotErr)
+			} else {
+				gotStatusCode := ResolveHTTPError(gotErr).StatusCode()
+				assert.Equal(test, data.wantStatusCode, gotStatusCode)
+			}
+			if data.wantErr != nil {
+				assert.True(test, errors.Is(gotErr, data.wantErr))
+			}
+			if !data.withoutBody && data.args.body != "" {
+				assert.True(test, body.closed)
+			}
+			// the body isn't drained after exceeding of the limit,
+			// because the connection will be closed anyway
+			if !data.withoutBody && data.args.body != "" &&
+				data.wantErr != ErrDataTooLarge {
+				_, err := body.ReadByte()
+				assert.Equal(test, io.EOF, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package httputils
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// NDJSONContentType ...
+//
+// It's the media type of newline-delimited JSON.
+// See: https://github.com/ndjson/ndjson-spec
+//
+const NDJSONContentType = "application/x-ndjson"
+
+const defaultFlushInterval = 100
+
+// JSONStreamIterator ...
+//
+// It returns the next item of a stream. The ok flag is false when the stream
+// is over; in this case, the item is ignored.
+//
+type JSONStreamIterator func() (item interface{}, ok bool, err error)
+
+// JSONStreamOption ...
+//
+// It sets an optional parameter of the WriteJSONArrayStream()
+// and WriteNDJSONStream() functions.
+//
+type JSONStreamOption func(options *jsonStreamOptions)
+
+type jsonStreamOptions struct {
+	flushInterval int
+}
+
+type jsonStreamFormat struct {
+	contentType string
+	prefix      string
+	separator   string
+	itemSuffix  string
+	suffix      string
+}
+
+var (
+	jsonArrayStreamFormat = jsonStreamFormat{
+		contentType: "application/json",
+		prefix:      "[",
+		separator:   ",",
+		suffix:      "]",
+	}
+	ndjsonStreamFormat = jsonStreamFormat{
+		contentType: NDJSONContentType,
+		itemSuffix:  "\n",
+	}
+)
+
+// WithFlushInterval ...
+//
+// It sets the number of items after which the written data is flushed
+// (if the writer implements the http.Flusher interface). By default,
+// it's 100 items. A non-positive value disables the periodical flushing.
+//
+// The written data is always flushed at the end of the stream.
+//
+func WithFlushInterval(itemCount int) JSONStreamOption {
+	return func(options *jsonStreamOptions) {
+		options.flushInterval = itemCount
+	}
+}
+
+// ChannelJSONStreamIterator ...
+//
+// It returns the iterator over the items received from the channel.
+// The stream is over when the channel is closed.
+//
+func ChannelJSONStreamIterator(items <-chan interface{}) JSONStreamIterator {
+	return func() (item interface{}, ok bool, err error) {
+		item, ok = <-items
+		return item, ok, nil
+	}
+}
+
+// WriteJSONArrayStream ...
+//
+// It marshals items of the stream one by one and writes them in the writer
+// as a JSON array, so that the whole data isn't held in memory. This function
+// also sets the corresponding content type and the specified status code.
+//
+// The header is written before the first item. So, if the iterator
+// or the marshalling of the first item failed, neither the content type
+// nor the status code will be set. Otherwise, the response will be incomplete
+// in case of an error.
+//
+// The writing stops at the first error, including errors of the writing
+// and the flushing (see the CatchingResponseWriter type).
+//
+func WriteJSONArrayStream(
+	writer http.ResponseWriter,
+	statusCode int,
+	iterator JSONStreamIterator,
+	options ...JSONStreamOption,
+) error {
+	return writeJSONStream(
+		writer,
+		statusCode,
+		iterator,
+		jsonArrayStreamFormat,
+		options,
+	)
+}
+
+// WriteNDJSONStream ...
+//
+// It's an analog of the WriteJSONArrayStream() function that writes items
+// as newline-delimited JSON (see the NDJSONContentType constant).
+//
+func WriteNDJSONStream(
+	writer http.ResponseWriter,
+	statusCode int,
+	iterator JSONStreamIterator,
+	options ...JSONStreamOption,
+) error {
+	return writeJSONStream(
+		writer,
+		statusCode,
+		iterator,
+		ndjsonStreamFormat,
+		options,
+	)
+}
+
+func writeJSONStream(
+	writer http.ResponseWriter,
+	statusCode int,
+	iterator JSONStreamIterator,
+	format jsonStreamFormat,
+	options []JSONStreamOption,
+) error {
+	streamOptions := jsonStreamOptions{flushInterval: defaultFlushInterval}
+	for _, option := range options {
+		option(&streamOptions)
+	}
+
+	stream := newJSONStream(writer, statusCode, format.contentType)
+	for itemCount := 0; ; itemCount++ {
+		item, ok, err := iterator()
+		if err != nil {
+			return errors.Wrapf(err, "unable to get the item #%d", itemCount)
+		}
+		if !ok {
+			if itemCount == 0 {
+				if err := stream.write([]byte(format.prefix)); err != nil {
+					return err
+				}
+			}
+
+			break
+		}
+
+		itemBytes, err := json.Marshal(item)
+		if err != nil {
+			return errors.Wrapf(err, "unable to marshal the item #%d", itemCount)
+		}
+
+		delimiter := format.separator
+		if itemCount == 0 {
+			delimiter = format.prefix
+		}
+		chunk := append([]byte(delimiter), itemBytes...)
+		chunk = append(chunk, format.itemSuffix...)
+		if err := stream.write(chunk); err != nil {
+			return err
+		}
+
+		if streamOptions.flushInterval > 0 &&
+			(itemCount+1)%streamOptions.flushInterval == 0 {
+			if err := stream.flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := stream.write([]byte(format.suffix)); err != nil {
+		return err
+	}
+
+	return stream.flush()
+}
+
+type jsonStream struct {
+	writer      *CatchingResponseWriter
+	statusCode  int
+	contentType string
+	unflushed   bool
+}
+
+func newJSONStream(
+	writer http.ResponseWriter,
+	statusCode int,
+	contentType string,
+) *jsonStream {
+	return &jsonStream{
+		writer:      NewCatchingResponseWriter(writer),
+		statusCode:  statusCode,
+		contentType: contentType,
+	}
+}
+
+func (stream *jsonStream) write(data []byte) error {
+	if !stream.writer.HeaderWritten() {
+		stream.writer.Header().Set("Content-Type", stream.contentType)
+		stream.writer.WriteHeader(stream.statusCode)
+		stream.unflushed = true
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	stream.unflushed = true
+
+	if _, err := stream.writer.Write(data); err != nil {
+		return errors.Wrap(err, "unable to write the data")
+	}
+
+	return nil
+}
+
+func (stream *jsonStream) flush() error {
+	if !stream.unflushed {
+		return nil
+	}
+	if _, ok := stream.writer.ResponseWriter.(http.Flusher); !ok {
+		return nil
+	}
+
+	stream.unflushed = false
+
+	if err := (*catchingFlusher)(stream.writer).FlushError(); err != nil {
+		return errors.Wrap(err, "unable to flush the data")
+	}
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package httputils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/iotest"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newSliceJSONStreamIterator(items ...interface{}) JSONStreamIterator {
+	return func() (item interface{}, ok bool, err error) {
+		if len(items) == 0 {
+			return nil, false, nil
+		}
+
+		item, items = items[0], items[1:]
+		return item, true, nil
+	}
+}
+
+func TestChannelJSONStreamIterator(test *testing.T) {
+	items := make(chan interface{}, 2)
+	items <- 23
+	items <- 42
+	close(items)
+
+	var gotItems []interface{}
+	iterator := ChannelJSONStreamIterator(items)
+	for {
+		item, ok, err := iterator()
+		assert.NoError(test, err)
+		if !ok {
+			break
+		}
+
+		gotItems = append(gotItems, item)
+	}
+
+	assert.Equal(test, []interface{}{23, 42}, gotItems)
+}
+
+func TestWriteJSONArrayStream(test *testing.T) {
+	type args struct {
+		iterator JSONStreamIterator
+		options  []JSONStreamOption
+	}
+
+	for _, data := range []struct {
+		name            string
+		args            args
+		wantStatusCode  int
+		wantContentType string
+		wantBody        string
+		wantFlushed     bool
+		wantErr         assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/few items",
+			args: args{
+				iterator: newSliceJSONStreamIterator(
+					map[string]int{"number": 23},
+					"test",
+					nil,
+				),
+				options: []JSONStreamOption{WithFlushInterval(2)},
+			},
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "application/json",
+			wantBody:        `[{"number":23},"test",null]`,
+			wantFlushed:     true,
+			wantErr:         assert.NoError,
+		},
+		{
+			name: "success/without items",
+			args: args{
+				iterator: newSliceJSONStreamIterator(),
+				options:  nil,
+			},
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "application/json",
+			wantBody:        `[]`,
+			wantFlushed:     true,
+			wantErr:         assert.NoError,
+		},
+		{
+			name: "error with the iterator before the first item",
+			args: args{
+				iterator: func() (interface{}, bool, error) {
+					return nil, false, iotest.ErrTimeout
+				},
+				options: nil,
+			},
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "",
+			wantBody:        "",
+			wantFlushed:     false,
+			wantErr:         assert.Error,
+		},
+		{
+			name: "error with the marshalling",
+			args: args{
+				iterator: newSliceJSONStreamIterator(23, func() {}),
+				options:  nil,
+			},
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "application/json",
+			wantBody:        `[23`,
+			wantFlushed:     false,
+			wantErr:         assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			recorder := httptest.NewRecorder()
+			err := WriteJSONArrayStream(
+				recorder,
+				http.StatusOK,
+				data.args.iterator,
+				data.args.options...,
+			)
+
+			assert.Equal(test, data.wantStatusCode, recorder.Code)
+			assert.Equal(
+				test,
+				data.wantContentType,
+				recorder.Header().Get("Content-Type"),
+			)
+			assert.Equal(test, data.wantBody, recorder.Body.String())
+			assert.Equal(test, data.wantFlushed, recorder.Flushed)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestWriteNDJSONStream(test *testing.T) {
+	type args struct {
+		writer   http.ResponseWriter
+		iterator JSONStreamIterator
+	}
+
+	for _, data := range []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name: "success",
+			args: args{
+				writer: func() http.ResponseWriter {
+					writer := new(MockExtendedResponseWriter)
+					writer.On("Header").Return(http.Header{})
+					writer.On("WriteHeader", http.StatusCreated).Return()
+					writer.On("Write", []byte("23\n")).Return(3, nil)
+					writer.On("Write", []byte(`"test"`+"\n")).Return(7, nil)
+					writer.On("FlushError").Return(nil).Twice()
+
+					return writer
+				}(),
+				iterator: newSliceJSONStreamIterator(23, "test"),
+			},
+			wantErr: nil,
+		},
+		{
+			name: "error with the writing",
+			args: args{
+				writer: func() http.ResponseWriter {
+					writer := new(MockResponseWriter)
+					writer.On("Header").Return(http.Header{})
+					writer.On("WriteHeader", http.StatusCreated).Return()
+					writer.On("Write", []byte("23\n")).Return(0, iotest.ErrTimeout)
+
+					return writer
+				}(),
+				iterator: newSliceJSONStreamIterator(23, "test"),
+			},
+			wantErr: iotest.ErrTimeout,
+		},
+		{
+			name: "error with the flushing",
+			args: args{
+				writer: func() http.ResponseWriter {
+					writer := new(MockExtendedResponseWriter)
+					writer.On("Header").Return(http.Header{})
+					writer.On("WriteHeader", http.StatusCreated).Return()
+					writer.On("Write", []byte("23\n")).Return(3, nil)
+					writer.On("FlushError").Return(iotest.ErrTimeout)
+
+					return writer
+				}(),
+				iterator: newSliceJSONStreamIterator(23, "test"),
+			},
+			wantErr: iotest.ErrTimeout,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			err := WriteNDJSONStream(
+				data.args.writer,
+				http.StatusCreated,
+				data.args.iterator,
+				WithFlushInterval(1),
+			)
+
+			wantHeader := http.Header{"Content-Type": {NDJSONContentType}}
+			mock.AssertExpectationsForObjects(test, data.args.writer)
+			assert.Equal(test, wantHeader, data.args.writer.Header())
+			assert.Equal(test, data.wantErr, errors.Cause(err))
+		})
+	}
+}
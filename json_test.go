@@ -1,11 +1,16 @@
 package httputils
 
 import (
+	"encoding/json"
 	"io"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"testing/iotest"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -143,6 +148,187 @@ func TestReadJSON(test *testing.T) {
 	}
 }
 
+func TestReadJSONWithOptions(test *testing.T) {
+	type args struct {
+		reader  io.Reader
+		data    interface{}
+		options []ReadJSONOption
+	}
+	type testData struct {
+		FieldOne int
+		FieldTwo string
+	}
+
+	for _, data := range []struct {
+		name           string
+		args           args
+		wantData       interface{}
+		wantErr        assert.ErrorAssertionFunc
+		wantStatusCode int
+	}{
+		{
+			name: "success without options",
+			args: args{
+				reader:  strings.NewReader(`{"FieldOne": 23, "FieldTwo": "test"}` + "\n"),
+				data:    new(testData),
+				options: nil,
+			},
+			wantData:       &testData{FieldOne: 23, FieldTwo: "test"},
+			wantErr:        assert.NoError,
+			wantStatusCode: 0,
+		},
+		{
+			name: "success with the allowed trailing data",
+			args: args{
+				reader:  strings.NewReader(`{"FieldOne": 23, "FieldTwo": "test"} garbage`),
+				data:    new(testData),
+				options: []ReadJSONOption{WithAllowedTrailingData()},
+			},
+			wantData:       &testData{FieldOne: 23, FieldTwo: "test"},
+			wantErr:        assert.NoError,
+			wantStatusCode: 0,
+		},
+		{
+			name: "success with all options",
+			args: args{
+				reader: strings.NewReader(`{"FieldOne": 23, "FieldTwo": "test"} `),
+				data:   new(testData),
+				options: []ReadJSONOption{
+					WithMaxSize(37),
+					WithDisallowedUnknownFields(),
+					WithAllowedTrailingData(),
+					WithUseNumber(),
+				},
+			},
+			wantData:       &testData{FieldOne: 23, FieldTwo: "test"},
+			wantErr:        assert.NoError,
+			wantStatusCode: 0,
+		},
+		{
+			name: "success with the use of numbers",
+			args: args{
+				reader:  strings.NewReader(`{"number": 23}`),
+				data:    new(map[string]interface{}),
+				options: []ReadJSONOption{WithUseNumber()},
+			},
+			wantData:       &map[string]interface{}{"number": json.Number("23")},
+			wantErr:        assert.NoError,
+			wantStatusCode: 0,
+		},
+		{
+			name: "error with a nil pointer",
+			args: args{
+				reader:  new(MockReader),
+				data:    (*testData)(nil),
+				options: nil,
+			},
+			wantData:       (*testData)(nil),
+			wantErr:        assert.Error,
+			wantStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name: "error with the data reading",
+			args: args{
+				reader: func() io.Reader {
+					reader := new(MockReader)
+					reader.
+						On("Read", mock.AnythingOfType("[]uint8")).
+						Return(0, iotest.ErrTimeout)
+
+					return reader
+				}(),
+				data:    new(testData),
+				options: nil,
+			},
+			wantData:       new(testData),
+			wantErr:        assert.Error,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "error with the maximal size",
+			args: args{
+				reader:  strings.NewReader(`{"FieldOne": 23, "FieldTwo": "test"}`),
+				data:    new(testData),
+				options: []ReadJSONOption{WithMaxSize(10)},
+			},
+			wantData:       new(testData),
+			wantErr:        assert.Error,
+			wantStatusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "error with the limit of the http.MaxBytesReader() function",
+			args: args{
+				reader: http.MaxBytesReader(
+					httptest.NewRecorder(),
+					ioutil.NopCloser(
+						strings.NewReader(`{"FieldOne": 23, "FieldTwo": "test"}`),
+					),
+					10,
+				),
+				data:    new(testData),
+				options: nil,
+			},
+			wantData:       new(testData),
+			wantErr:        assert.Error,
+			wantStatusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "error with an empty data",
+			args: args{
+				reader:  strings.NewReader(""),
+				data:    new(testData),
+				options: nil,
+			},
+			wantData:       new(testData),
+			wantErr:        assert.Error,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "error with unknown fields",
+			args: args{
+				reader:  strings.NewReader(`{"FieldOne": 23, "FieldThree": "test"}`),
+				data:    new(testData),
+				options: []ReadJSONOption{WithDisallowedUnknownFields()},
+			},
+			wantData:       &testData{FieldOne: 23},
+			wantErr:        assert.Error,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "error with trailing data",
+			args: args{
+				reader:  strings.NewReader(`{"FieldOne": 23, "FieldTwo": "test"} garbage`),
+				data:    new(testData),
+				options: nil,
+			},
+			wantData:       &testData{FieldOne: 23, FieldTwo: "test"},
+			wantErr:        assert.Error,
+			wantStatusCode: http.StatusBadRequest,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			gotErr := ReadJSONWithOptions(
+				data.args.reader,
+				data.args.data,
+				data.args.options...,
+			)
+
+			if reader, ok := data.args.reader.(*MockReader); ok {
+				mock.AssertExpectationsForObjects(test, reader)
+			}
+			assert.Equal(test, data.wantData, data.args.data)
+			data.wantErr(test, gotErr)
+			if gotErr != nil {
+				gotStatusCode := ResolveHTTPError(gotErr).StatusCode()
+				assert.Equal(test, data.wantStatusCode, gotStatusCode)
+			}
+			if data.wantStatusCode == http.StatusRequestEntityTooLarge {
+				assert.True(test, errors.Is(gotErr, ErrDataTooLarge))
+			}
+		})
+	}
+}
+
 func TestWriteJSON(test *testing.T) {
 	type args struct {
 		writer     http.ResponseWriter
@@ -220,3 +406,132 @@ func TestWriteJSON(test *testing.T) {
 		})
 	}
 }
+
+func TestWriteJSONWithOptions(test *testing.T) {
+	type args struct {
+		data    interface{}
+		options []WriteJSONOption
+	}
+	type testData struct {
+		FieldOne int
+		FieldTwo string
+	}
+
+	newRequest := func(target string, header http.Header) *http.Request {
+		request := httptest.NewRequest(http.MethodGet, target, nil)
+		for key, values := range header {
+			request.Header[key] = values
+		}
+
+		return request
+	}
+
+	for _, data := range []struct {
+		name       string
+		args       args
+		wantHeader http.Header
+		wantBody   string
+		wantErr    assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success without options",
+			args: args{
+				data:    testData{FieldOne: 23, FieldTwo: "<test>"},
+				options: nil,
+			},
+			wantHeader: http.Header{"Content-Type": {"application/json"}},
+			wantBody:   `{"FieldOne":23,"FieldTwo":"\u003ctest\u003e"}`,
+			wantErr:    assert.NoError,
+		},
+		{
+			name: "success with all options",
+			args: args{
+				data: testData{FieldOne: 23, FieldTwo: "<test>"},
+				options: []WriteJSONOption{
+					WithIndent("\t"),
+					WithDisabledHTMLEscaping(),
+					WithContentType("application/vnd.api+json"),
+					WithTrailingNewline(),
+					WithHeader("Cache-Control", "no-cache"),
+					WithHeader("Link", "</one>"),
+					WithHeader("Link", "</two>"),
+				},
+			},
+			wantHeader: http.Header{
+				"Content-Type":  {"application/vnd.api+json"},
+				"Cache-Control": {"no-cache"},
+				"Link":          {"</one>", "</two>"},
+			},
+			wantBody: "{\n\t\"FieldOne\": 23,\n\t\"FieldTwo\": \"<test>\"\n}\n",
+			wantErr:  assert.NoError,
+		},
+		{
+			name: "success with the requested pretty print/query parameter",
+			args: args{
+				data: testData{FieldOne: 23, FieldTwo: "test"},
+				options: []WriteJSONOption{
+					WithRequestedPrettyPrint(newRequest("/?pretty", nil)),
+				},
+			},
+			wantHeader: http.Header{"Content-Type": {"application/json"}},
+			wantBody:   "{\n  \"FieldOne\": 23,\n  \"FieldTwo\": \"test\"\n}",
+			wantErr:    assert.NoError,
+		},
+		{
+			name: "success with the requested pretty print/header",
+			args: args{
+				data: testData{FieldOne: 23, FieldTwo: "test"},
+				options: []WriteJSONOption{
+					WithRequestedPrettyPrint(newRequest(
+						"/",
+						http.Header{"X-Pretty-Print": {"true"}},
+					)),
+				},
+			},
+			wantHeader: http.Header{"Content-Type": {"application/json"}},
+			wantBody:   "{\n  \"FieldOne\": 23,\n  \"FieldTwo\": \"test\"\n}",
+			wantErr:    assert.NoError,
+		},
+		{
+			name: "success with the requested pretty print/disabled",
+			args: args{
+				data: testData{FieldOne: 23, FieldTwo: "test"},
+				options: []WriteJSONOption{
+					WithRequestedPrettyPrint(newRequest(
+						"/?pretty=false",
+						http.Header{"X-Pretty-Print": {"incorrect"}},
+					)),
+				},
+			},
+			wantHeader: http.Header{"Content-Type": {"application/json"}},
+			wantBody:   `{"FieldOne":23,"FieldTwo":"test"}`,
+			wantErr:    assert.NoError,
+		},
+		{
+			name: "error with the marshalling",
+			args: args{
+				data: func() {},
+				options: []WriteJSONOption{
+					WithHeader("Cache-Control", "no-cache"),
+				},
+			},
+			wantHeader: http.Header{},
+			wantBody:   "",
+			wantErr:    assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			recorder := httptest.NewRecorder()
+			gotErr := WriteJSONWithOptions(
+				recorder,
+				http.StatusCreated,
+				data.args.data,
+				data.args.options...,
+			)
+
+			assert.Equal(test, data.wantHeader, recorder.Header())
+			assert.Equal(test, data.wantBody, recorder.Body.String())
+			data.wantErr(test, gotErr)
+		})
+	}
+}
@@ -70,3 +70,19 @@ type FileSystem interface {
 type Reader interface {
 	io.Reader
 }
+
+//go:generate mockery --name=ExtendedResponseWriter --inpackage --case=underscore --testonly
+
+// ExtendedResponseWriter ...
+//
+// It's used only for mock generating.
+type ExtendedResponseWriter interface {
+	http.ResponseWriter
+	http.Flusher
+	http.Hijacker
+	http.Pusher
+	io.ReaderFrom
+	http.CloseNotifier // nolint: staticcheck
+
+	FlushError() error
+}
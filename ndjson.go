@@ -0,0 +1,277 @@
+package httputils
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+const defaultMaxLineSize = 1 << 20
+
+// ErrLineTooLong ...
+//
+// It's returned (in a wrapped form) by the ReadNDJSON() function when a line
+// exceeds the maximal size. It's resolved
+// to the http.StatusRequestEntityTooLarge status code (see
+// the ResolveHTTPError() function).
+//
+var ErrLineTooLong = errors.New("the line is too long")
+
+// ErrTooManyRecords ...
+//
+// It's returned (in a wrapped form) by the ReadNDJSON() function when
+// the number of records exceeds the maximal one. It's resolved
+// to the http.StatusRequestEntityTooLarge status code (see
+// the ResolveHTTPError() function).
+//
+var ErrTooManyRecords = errors.New("there are too many records")
+
+// LineError ...
+//
+// It annotates an error with the number of the line (starting from 1)
+// where it occurred.
+//
+type LineError struct {
+	LineNumber int
+	Err        error
+}
+
+// NDJSONRecordFactory ...
+//
+// It returns a new non-nil pointer to unmarshal a record into.
+//
+type NDJSONRecordFactory func() interface{}
+
+// NDJSONRecordHandler ...
+//
+// It handles the record unmarshalled from the line with the specified number.
+//
+type NDJSONRecordHandler func(lineNumber int, record interface{}) error
+
+// ReadNDJSONOption ...
+//
+// It sets an optional parameter of the ReadNDJSON() function.
+//
+type ReadNDJSONOption func(options *readNDJSONOptions)
+
+type readNDJSONOptions struct {
+	maxLineSize           int
+	maxRecordCount        int
+	skipBadLines          bool
+	badLineReporter       func(err error)
+	validator             ValidatorFunc
+	disallowUnknownFields bool
+}
+
+// WithMaxLineSize ...
+//
+// It sets the maximal size of a line in bytes (without the line ending).
+// By default, it's 1 MiB. A non-positive value makes the size unlimited.
+//
+func WithMaxLineSize(maxLineSize int) ReadNDJSONOption {
+	return func(options *readNDJSONOptions) {
+		options.maxLineSize = maxLineSize
+	}
+}
+
+// WithMaxRecordCount ...
+//
+// It sets the maximal number of records (skipped bad lines are not counted).
+// By default, the number is unlimited.
+//
+func WithMaxRecordCount(maxRecordCount int) ReadNDJSONOption {
+	return func(options *readNDJSONOptions) {
+		options.maxRecordCount = maxRecordCount
+	}
+}
+
+// WithSkippedBadLines ...
+//
+// It makes the reading skip bad lines (too long, malformed or invalid ones)
+// instead of aborting. The reporter is called for each skipped line
+// with the LineError error; it may be nil.
+//
+// Errors of the record handler are not affected by this option.
+//
+func WithSkippedBadLines(reporter func(err error)) ReadNDJSONOption {
+	return func(options *readNDJSONOptions) {
+		options.skipBadLines = true
+		options.badLineReporter = reporter
+	}
+}
+
+// WithRecordValidator ...
+//
+// It sets the function that validates each record after the unmarshalling
+// (in addition to the Validator interface). See the WithValidator() option
+// for details.
+//
+func WithRecordValidator(validator ValidatorFunc) ReadNDJSONOption {
+	return func(options *readNDJSONOptions) {
+		options.validator = validator
+	}
+}
+
+// WithDisallowedUnknownRecordFields ...
+//
+// It's an analog of the WithDisallowedUnknownFields() option for records.
+//
+func WithDisallowedUnknownRecordFields() ReadNDJSONOption {
+	return func(options *readNDJSONOptions) {
+		options.disallowUnknownFields = true
+	}
+}
+
+// ReadNDJSON ...
+//
+// It reads newline-delimited JSON (see the NDJSONContentType constant)
+// from the reader line by line. Each non-blank line is unmarshalled
+// into a new record created by the factory and validated (see the Validator
+// interface), and then the record is passed to the handler.
+//
+// Errors related to a line (the exceeding of the maximal line size,
+// the unmarshalling and the validation) are annotated by the LineError type
+// and marked according to the ResolveHTTPError() function. By default,
+// the reading aborts at the first such error; see the WithSkippedBadLines()
+// option.
+//
+// If the reader is the io.ReadCloser interface, this function
+// does not close it.
+//
+func ReadNDJSON(
+	reader io.Reader,
+	factory NDJSONRecordFactory,
+	handler NDJSONRecordHandler,
+	options ...ReadNDJSONOption,
+) error {
+	readNDJSONOptions := readNDJSONOptions{maxLineSize: defaultMaxLineSize}
+	for _, option := range options {
+		option(&readNDJSONOptions)
+	}
+
+	bufferedReader := bufio.NewReader(reader)
+	var recordCount int
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := readLine(bufferedReader, readNDJSONOptions.maxLineSize)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil && err != ErrLineTooLong {
+			return errors.Wrap(newReadingError(err), "unable to read the data")
+		}
+		if err == nil && len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		if readNDJSONOptions.maxRecordCount > 0 &&
+			recordCount == readNDJSONOptions.maxRecordCount {
+			err := newTooLargeError(ErrTooManyRecords)
+			return errors.Wrap(err, "unable to read the data")
+		}
+
+		var record interface{}
+		if err == ErrLineTooLong {
+			err = newTooLargeError(err)
+		} else {
+			record = factory()
+			err = unmarshalRecord(line, record, readNDJSONOptions)
+		}
+		if err != nil {
+			err = LineError{LineNumber: lineNumber, Err: err}
+			if !readNDJSONOptions.skipBadLines {
+				return errors.Wrap(err, "unable to read the record")
+			}
+
+			if readNDJSONOptions.badLineReporter != nil {
+				readNDJSONOptions.badLineReporter(err)
+			}
+
+			continue
+		}
+
+		recordCount++
+		if err := handler(lineNumber, record); err != nil {
+			return errors.Wrapf(
+				err,
+				"unable to handle the record on the line #%d",
+				lineNumber,
+			)
+		}
+	}
+}
+
+func (err LineError) Error() string {
+	return fmt.Sprintf("line #%d: %v", err.LineNumber, err.Err)
+}
+
+// Cause ...
+//
+// It returns the annotated error. It's used by the errors.Cause() function.
+//
+func (err LineError) Cause() error {
+	return err.Err
+}
+
+// Unwrap ...
+//
+// It returns the annotated error. It's used by the errors.Is()
+// and errors.As() functions.
+//
+func (err LineError) Unwrap() error {
+	return err.Err
+}
+
+// it reads the next line without the line ending; if the line is too long,
+// it's skipped entirely and the ErrLineTooLong error is returned
+func readLine(reader *bufio.Reader, maxSize int) ([]byte, error) {
+	var line []byte
+	var tooLong bool
+	for {
+		fragment, err := reader.ReadSlice('\n')
+		if err != nil && err != bufio.ErrBufferFull && err != io.EOF {
+			return nil, err
+		}
+		if err == io.EOF && len(line) == 0 && len(fragment) == 0 && !tooLong {
+			return nil, io.EOF
+		}
+
+		if !tooLong {
+			// the fragment is only valid until the next reading
+			line = append(line, fragment...)
+			// the line ending isn't trimmed yet, so take it into account
+			if maxSize > 0 && len(line) > maxSize+len("\r\n") {
+				line, tooLong = nil, true
+			}
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+
+		line = bytes.TrimSuffix(line, []byte("\n"))
+		line = bytes.TrimSuffix(line, []byte("\r"))
+		if tooLong || (maxSize > 0 && len(line) > maxSize) {
+			return nil, ErrLineTooLong
+		}
+
+		return line, nil
+	}
+}
+
+func unmarshalRecord(
+	line []byte,
+	record interface{},
+	options readNDJSONOptions,
+) error {
+	decodingOptions := []ReadJSONOption{
+		WithValidator(options.validator),
+	}
+	if options.disallowUnknownFields {
+		option := WithDisallowedUnknownFields()
+		decodingOptions = append(decodingOptions, option)
+	}
+
+	return ReadJSONWithOptions(bytes.NewReader(line), record, decodingOptions...)
+}
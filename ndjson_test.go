@@ -0,0 +1,215 @@
+package httputils
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadNDJSON(test *testing.T) {
+	type args struct {
+		reader  io.Reader
+		options []ReadNDJSONOption
+	}
+	type testRecord struct {
+		Number int
+	}
+	type handledRecord struct {
+		lineNumber int
+		record     interface{}
+	}
+
+	for _, data := range []struct {
+		name             string
+		args             args
+		handlerErr       error
+		wantRecords      []handledRecord
+		wantBadLines     []string
+		wantErr          string
+		wantStatusCode   int
+		wantLineNumber   int
+		wantErrIsLineErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				reader: strings.NewReader(
+					"{\"Number\": 23}\r\n\n  \n{\"Number\": 42}",
+				),
+				options: nil,
+			},
+			handlerErr: nil,
+			wantRecords: []handledRecord{
+				{lineNumber: 1, record: &testRecord{Number: 23}},
+				{lineNumber: 4, record: &testRecord{Number: 42}},
+			},
+			wantBadLines: nil,
+			wantErr:      "",
+		},
+		{
+			name: "success with skipped bad lines",
+			args: args{
+				reader: strings.NewReader(
+					"{\"Number\": 23}\n" +
+						"{\"Number\": 100500100500}\n" +
+						"incorrect\n" +
+						"{\"Number\":5} {}\n" +
+						"{\"Unknown\":5}\n" +
+						"{\"Number\": -1}\n" +
+						"{\"Number\": 12}\n",
+				),
+				options: []ReadNDJSONOption{
+					WithMaxLineSize(15),
+					WithMaxRecordCount(2),
+					WithDisallowedUnknownRecordFields(),
+					WithRecordValidator(func(record interface{}) error {
+						var validationErr ValidationError
+						if record.(*testRecord).Number < 0 {
+							validationErr.Add("Number", "is negative")
+						}
+
+						return validationErr.ErrorOrNil()
+					}),
+				},
+			},
+			handlerErr: nil,
+			wantRecords: []handledRecord{
+				{lineNumber: 1, record: &testRecord{Number: 23}},
+				{lineNumber: 7, record: &testRecord{Number: 12}},
+			},
+			wantBadLines: []string{
+				"line #2: the line is too long",
+				"line #3: unable to unmarshal the data: " +
+					"invalid character 'i' looking for beginning of value",
+				"line #4: unable to unmarshal the data: " +
+					"the data contains trailing data after the JSON value",
+				"line #5: unable to unmarshal the data: " +
+					"json: unknown field \"Unknown\"",
+				"line #6: unable to validate the data: " +
+					"the data is invalid: Number: is negative",
+			},
+			wantErr: "",
+		},
+		{
+			name: "error with the data reading",
+			args: args{
+				reader: iotest.TimeoutReader(
+					iotest.OneByteReader(strings.NewReader("{\"Number\": 23}\n")),
+				),
+				options: nil,
+			},
+			handlerErr:     nil,
+			wantRecords:    nil,
+			wantBadLines:   nil,
+			wantErr:        "unable to read the data: timeout",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "error with a bad line",
+			args: args{
+				reader:  strings.NewReader("{\"Number\": 23}\nincorrect\n"),
+				options: nil,
+			},
+			handlerErr: nil,
+			wantRecords: []handledRecord{
+				{lineNumber: 1, record: &testRecord{Number: 23}},
+			},
+			wantBadLines: nil,
+			wantErr: "unable to read the record: line #2: " +
+				"unable to unmarshal the data: " +
+				"invalid character 'i' looking for beginning of value",
+			wantStatusCode:   http.StatusBadRequest,
+			wantLineNumber:   2,
+			wantErrIsLineErr: true,
+		},
+		{
+			name: "error with a too long line",
+			args: args{
+				reader:  strings.NewReader("{\"Number\": 100500100500}\n"),
+				options: []ReadNDJSONOption{WithMaxLineSize(15)},
+			},
+			handlerErr:       nil,
+			wantRecords:      nil,
+			wantBadLines:     nil,
+			wantErr:          "unable to read the record: line #1: the line is too long",
+			wantStatusCode:   http.StatusRequestEntityTooLarge,
+			wantLineNumber:   1,
+			wantErrIsLineErr: true,
+		},
+		{
+			name: "error with too many records",
+			args: args{
+				reader:  strings.NewReader("{\"Number\": 23}\n{\"Number\": 42}\n"),
+				options: []ReadNDJSONOption{WithMaxRecordCount(1)},
+			},
+			handlerErr: nil,
+			wantRecords: []handledRecord{
+				{lineNumber: 1, record: &testRecord{Number: 23}},
+			},
+			wantBadLines:   nil,
+			wantErr:        "unable to read the data: there are too many records",
+			wantStatusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "error with the handler",
+			args: args{
+				reader:  strings.NewReader("{\"Number\": 23}\n{\"Number\": 42}\n"),
+				options: nil,
+			},
+			handlerErr: iotest.ErrTimeout,
+			wantRecords: []handledRecord{
+				{lineNumber: 1, record: &testRecord{Number: 23}},
+			},
+			wantBadLines:   nil,
+			wantErr:        "unable to handle the record on the line #1: timeout",
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			var gotRecords []handledRecord
+			var gotBadLines []string
+			options := append(
+				[]ReadNDJSONOption{
+					WithSkippedBadLines(func(err error) {
+						gotBadLines = append(gotBadLines, err.Error())
+					}),
+				},
+				data.args.options...,
+			)
+			if data.wantBadLines == nil {
+				options = data.args.options
+			}
+
+			err := ReadNDJSON(
+				data.args.reader,
+				func() interface{} { return new(testRecord) },
+				func(lineNumber int, record interface{}) error {
+					gotRecords = append(gotRecords, handledRecord{lineNumber, record})
+					return data.handlerErr
+				},
+				options...,
+			)
+
+			assert.Equal(test, data.wantRecords, gotRecords)
+			assert.Equal(test, data.wantBadLines, gotBadLines)
+			if data.wantErr == "" {
+				assert.NoError(test, err)
+				return
+			}
+
+			assert.EqualError(test, err, data.wantErr)
+			gotStatusCode := ResolveHTTPError(err).StatusCode()
+			assert.Equal(test, data.wantStatusCode, gotStatusCode)
+
+			var lineErr LineError
+			if assert.Equal(test, data.wantErrIsLineErr, errors.As(err, &lineErr)) {
+				assert.Equal(test, data.wantLineNumber, lineErr.LineNumber)
+			}
+		})
+	}
+}
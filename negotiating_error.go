@@ -0,0 +1,144 @@
+package httputils
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+
+	"github.com/go-log/log"
+	"github.com/pkg/errors"
+)
+
+// ErrorTemplateData ...
+//
+// It's passed to the HTML template registered
+// via the WithErrorHTMLTemplate() option.
+//
+type ErrorTemplateData struct {
+	StatusCode int
+	StatusText string
+	Message    string
+	Code       string
+}
+
+// NegotiatingErrorOption ...
+//
+// It sets an optional parameter of the NegotiatingLoggingError() function.
+//
+type NegotiatingErrorOption func(options *negotiatingErrorOptions)
+
+type negotiatingErrorOptions struct {
+	htmlTemplate *template.Template
+}
+
+// WithErrorHTMLTemplate ...
+//
+// It registers the HTML template used for the text/html media type.
+// The template receives the ErrorTemplateData structure. Without the template,
+// the text/html media type isn't offered.
+//
+func WithErrorHTMLTemplate(
+	htmlTemplate *template.Template,
+) NegotiatingErrorOption {
+	return func(options *negotiatingErrorOptions) {
+		options.htmlTemplate = htmlTemplate
+	}
+}
+
+// NegotiatingLoggingError ...
+//
+// It's an analog of the LoggingError() function that renders the error
+// in the media type selected according to the Accept header of the request:
+// text/plain (the default one), application/json (see the WriteJSONError()
+// function), application/problem+json (see the WriteProblem() function)
+// or text/html (see the WithErrorHTMLTemplate() option).
+//
+// The status code and the message are resolved the same way as in
+// the NewProblem() function, so internal details of server errors are not
+// exposed. An error of the rendering or the writing is logged via
+// the provided log.Logger interface too. If nothing is written because
+// of the error, the plain error with the http.StatusInternalServerError status
+// code is written instead.
+//
+func NegotiatingLoggingError(
+	logger log.Logger,
+	writer http.ResponseWriter,
+	request *http.Request,
+	err error,
+	statusCode int,
+	options ...NegotiatingErrorOption,
+) {
+	var negotiatingErrorOptions negotiatingErrorOptions
+	for _, option := range options {
+		option(&negotiatingErrorOptions)
+	}
+
+	logger.Log(err.Error())
+
+	offers := []string{"text/plain", "application/json", ProblemContentType}
+	if negotiatingErrorOptions.htmlTemplate != nil {
+		offers = append(offers, "text/html")
+	}
+
+	httpErr := resolveHTTPError(err, statusCode)
+	catchingWriter := NewCatchingResponseWriter(writer)
+	catchingWriter.Header().Add("Vary", "Accept")
+
+	var renderingErr error
+	switch negotiateMediaType(request.Header, offers) {
+	case "application/json":
+		renderingErr = writeJSON(
+			catchingWriter,
+			httpErr.StatusCode(),
+			"application/json",
+			newJSONError(httpErr),
+		)
+	case ProblemContentType:
+		problem := NewProblem(err, statusCode)
+		renderingErr =
+			writeJSON(catchingWriter, problem.Status, ProblemContentType, problem)
+	case "text/html":
+		renderingErr = writeHTMLError(
+			catchingWriter,
+			negotiatingErrorOptions.htmlTemplate,
+			httpErr,
+		)
+	default:
+		http.Error(catchingWriter, httpErr.PublicMessage(), httpErr.StatusCode())
+	}
+	if renderingErr == nil {
+		renderingErr = catchingWriter.LastError()
+	}
+	if renderingErr != nil {
+		logger.Logf("unable to write the error: %v", renderingErr)
+		writeFallbackError(catchingWriter)
+	}
+}
+
+func writeHTMLError(
+	writer http.ResponseWriter,
+	htmlTemplate *template.Template,
+	httpErr *HTTPError,
+) error {
+	var buffer bytes.Buffer
+	err := htmlTemplate.Execute(&buffer, ErrorTemplateData{
+		StatusCode: httpErr.StatusCode(),
+		StatusText: http.StatusText(httpErr.StatusCode()),
+		Message:    httpErr.PublicMessage(),
+		Code:       httpErr.Code(),
+	})
+	if err != nil {
+		// fall back to the plain text, so that the client gets the error anyway
+		http.Error(writer, httpErr.PublicMessage(), httpErr.StatusCode())
+		return errors.Wrap(err, "unable to execute the HTML template")
+	}
+
+	writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.WriteHeader(httpErr.StatusCode())
+	if _, err := writer.Write(buffer.Bytes()); err != nil {
+		return errors.Wrap(err, "unable to write the HTML")
+	}
+
+	return nil
+}
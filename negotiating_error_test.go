@@ -0,0 +1,190 @@
+package httputils
+
+import (
+	"encoding/json"
+	"html/template"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/iotest"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNegotiatingLoggingError(test *testing.T) {
+	type args struct {
+		accept     string
+		err        error
+		statusCode int
+		options    []NegotiatingErrorOption
+	}
+
+	htmlTemplate := template.Must(template.New("error").Parse(
+		"<h1>{{ .StatusCode }} {{ .StatusText }}</h1><p>{{ .Message }}</p>",
+	))
+	for _, data := range []struct {
+		name            string
+		args            args
+		wantLogs        [][]interface{}
+		wantStatusCode  int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name: "plain text",
+			args: args{
+				accept:     "",
+				err:        errors.Wrap(iotest.ErrTimeout, "dummy"),
+				statusCode: http.StatusBadRequest,
+				options:    nil,
+			},
+			wantLogs:        [][]interface{}{{"dummy: timeout"}},
+			wantStatusCode:  http.StatusBadRequest,
+			wantContentType: "text/plain; charset=utf-8",
+			wantBody:        "dummy: timeout\n",
+		},
+		{
+			name: "JSON",
+			args: args{
+				accept: "application/json",
+				err: errors.Wrap(
+					NotFound(iotest.ErrTimeout).WithCode("not_found"),
+					"dummy",
+				),
+				statusCode: http.StatusInternalServerError,
+				options:    nil,
+			},
+			wantLogs:        [][]interface{}{{"dummy: timeout"}},
+			wantStatusCode:  http.StatusNotFound,
+			wantContentType: "application/json",
+			wantBody:        `{"error":"dummy: timeout","code":"not_found"}`,
+		},
+		{
+			name: "problem details",
+			args: args{
+				accept:     "application/problem+json, application/json;q=0.5",
+				err:        errors.Wrap(iotest.ErrTimeout, "dummy"),
+				statusCode: http.StatusInternalServerError,
+				options:    nil,
+			},
+			wantLogs:        [][]interface{}{{"dummy: timeout"}},
+			wantStatusCode:  http.StatusInternalServerError,
+			wantContentType: ProblemContentType,
+			wantBody: `{"detail":"Internal Server Error","status":500,` +
+				`"title":"Internal Server Error","type":"about:blank"}`,
+		},
+		{
+			name: "HTML/without the template",
+			args: args{
+				accept:     "text/html",
+				err:        errors.Wrap(iotest.ErrTimeout, "dummy"),
+				statusCode: http.StatusBadRequest,
+				options:    nil,
+			},
+			wantLogs:        [][]interface{}{{"dummy: timeout"}},
+			wantStatusCode:  http.StatusBadRequest,
+			wantContentType: "text/plain; charset=utf-8",
+			wantBody:        "dummy: timeout\n",
+		},
+		{
+			name: "HTML/with the template",
+			args: args{
+				accept:     "text/html,application/xhtml+xml,*/*;q=0.8",
+				err:        errors.Wrap(iotest.ErrTimeout, "<dummy>"),
+				statusCode: http.StatusBadRequest,
+				options: []NegotiatingErrorOption{
+					WithErrorHTMLTemplate(htmlTemplate),
+				},
+			},
+			wantLogs:        [][]interface{}{{"<dummy>: timeout"}},
+			wantStatusCode:  http.StatusBadRequest,
+			wantContentType: "text/html; charset=utf-8",
+			wantBody: "<h1>400 Bad Request</h1>" +
+				"<p>&lt;dummy&gt;: timeout</p>",
+		},
+		{
+			name: "HTML/with the template error",
+			args: args{
+				accept:     "text/html",
+				err:        errors.Wrap(iotest.ErrTimeout, "dummy"),
+				statusCode: http.StatusBadRequest,
+				options: []NegotiatingErrorOption{
+					WithErrorHTMLTemplate(
+						template.Must(template.New("error").Parse("{{ .Unknown }}")),
+					),
+				},
+			},
+			wantLogs: [][]interface{}{
+				{"dummy: timeout"},
+				{
+					"unable to write the error: %v",
+					mock.MatchedBy(func(error) bool { return true }),
+				},
+			},
+			wantStatusCode:  http.StatusBadRequest,
+			wantContentType: "text/plain; charset=utf-8",
+			wantBody:        "dummy: timeout\n",
+		},
+		{
+			name: "problem details/with the marshalling error",
+			args: args{
+				accept: ProblemContentType,
+				err: testProblemError{
+					problem: Problem{
+						Extensions: map[string]interface{}{"value": math.NaN()},
+					},
+				},
+				statusCode: http.StatusBadRequest,
+				options:    nil,
+			},
+			wantLogs: [][]interface{}{
+				{"test problem error"},
+				{
+					"unable to write the error: %v",
+					mock.MatchedBy(func(err error) bool {
+						var unsupportedValueErr *json.UnsupportedValueError
+						return errors.As(err, &unsupportedValueErr)
+					}),
+				},
+			},
+			wantStatusCode:  http.StatusInternalServerError,
+			wantContentType: "text/plain; charset=utf-8",
+			wantBody:        "Internal Server Error\n",
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			logger := new(MockLogger)
+			for _, arguments := range data.wantLogs {
+				if len(arguments) == 1 {
+					logger.On("Log", arguments...).Return()
+				} else {
+					logger.On("Logf", arguments...).Return()
+				}
+			}
+
+			writer := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+			if data.args.accept != "" {
+				request.Header.Set("Accept", data.args.accept)
+			}
+
+			NegotiatingLoggingError(
+				logger,
+				writer,
+				request,
+				data.args.err,
+				data.args.statusCode,
+				data.args.options...,
+			)
+
+			mock.AssertExpectationsForObjects(test, logger)
+			assert.Equal(test, data.wantStatusCode, writer.Code)
+			assert.Equal(test, data.wantContentType, writer.Header().Get("Content-Type"))
+			assert.Equal(test, "Accept", writer.Header().Get("Vary"))
+			assert.Equal(test, data.wantBody, writer.Body.String())
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package httputils
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/golang/gddo/httputil/header"
+)
+
+const (
+	exactMediaRange = iota
+	subtypeWildcardMediaRange
+	fullWildcardMediaRange
+	unmatchedMediaRange
+)
+
+// it returns the most acceptable offer according to the Accept header,
+// or the empty string if no offers are acceptable; the quality values
+// are honoured, the most specific media range matching an offer
+// determines its quality, and ties are resolved by the order of the offers;
+// if the Accept header is missing, the first offer is returned
+func negotiateMediaType(requestHeader http.Header, offers []string) string {
+	specs := header.ParseAccept(requestHeader, "Accept")
+	if len(specs) == 0 {
+		if len(offers) == 0 {
+			return ""
+		}
+
+		return offers[0]
+	}
+
+	var bestOffer string
+	var bestQuality float64
+	for _, offer := range offers {
+		quality := mediaTypeQuality(specs, offer)
+		if quality > bestQuality {
+			bestOffer, bestQuality = offer, quality
+		}
+	}
+
+	return bestOffer
+}
+
+// it returns the quality of the media type according to the most specific
+// media range matching it; if no media ranges match, it returns zero
+func mediaTypeQuality(specs []header.AcceptSpec, mediaType string) float64 {
+	bestMatch, bestQuality := unmatchedMediaRange, 0.0
+	for _, spec := range specs {
+		match := matchMediaRange(spec.Value, mediaType)
+		if match == unmatchedMediaRange {
+			continue
+		}
+
+		if match < bestMatch || (match == bestMatch && spec.Q > bestQuality) {
+			bestMatch, bestQuality = match, spec.Q
+		}
+	}
+
+	return bestQuality
+}
+
+func matchMediaRange(mediaRange string, mediaType string) int {
+	switch {
+	case mediaRange == "*/*":
+		return fullWildcardMediaRange
+	case strings.HasSuffix(mediaRange, "/*"):
+		prefix := mediaRange[:len(mediaRange)-1]
+		if len(mediaType) >= len(prefix) &&
+			strings.EqualFold(mediaType[:len(prefix)], prefix) {
+			return subtypeWildcardMediaRange
+		}
+	case strings.EqualFold(mediaRange, mediaType):
+		return exactMediaRange
+	}
+
+	return unmatchedMediaRange
+}
@@ -0,0 +1,107 @@
+package httputils
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_negotiateMediaType(test *testing.T) {
+	type args struct {
+		requestHeader http.Header
+		offers        []string
+	}
+
+	offers := []string{"text/plain", "application/json", "text/html"}
+	for _, data := range []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "without the Accept header",
+			args: args{
+				requestHeader: http.Header{},
+				offers:        offers,
+			},
+			want: "text/plain",
+		},
+		{
+			name: "without the Accept header and offers",
+			args: args{
+				requestHeader: http.Header{},
+				offers:        nil,
+			},
+			want: "",
+		},
+		{
+			name: "with an exact media range",
+			args: args{
+				requestHeader: http.Header{"Accept": {"application/json"}},
+				offers:        offers,
+			},
+			want: "application/json",
+		},
+		{
+			name: "with an exact media range in other case",
+			args: args{
+				requestHeader: http.Header{"Accept": {"Application/JSON"}},
+				offers:        offers,
+			},
+			want: "application/json",
+		},
+		{
+			name: "with quality values",
+			args: args{
+				requestHeader: http.Header{
+					"Accept": {"text/plain;q=0.5, application/json;q=0.8"},
+				},
+				offers: offers,
+			},
+			want: "application/json",
+		},
+		{
+			name: "with a browser",
+			args: args{
+				requestHeader: http.Header{
+					"Accept": {
+						"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+					},
+				},
+				offers: offers,
+			},
+			want: "text/html",
+		},
+		{
+			name: "with a subtype wildcard",
+			args: args{
+				requestHeader: http.Header{"Accept": {"text/*;q=0.5, */*;q=0.1"}},
+				offers:        []string{"application/json", "text/html"},
+			},
+			want: "text/html",
+		},
+		{
+			name: "with an explicit exclusion",
+			args: args{
+				requestHeader: http.Header{"Accept": {"text/plain;q=0, */*"}},
+				offers:        offers,
+			},
+			want: "application/json",
+		},
+		{
+			name: "without acceptable offers",
+			args: args{
+				requestHeader: http.Header{"Accept": {"image/png"}},
+				offers:        offers,
+			},
+			want: "",
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := negotiateMediaType(data.args.requestHeader, data.args.offers)
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
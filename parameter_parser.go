@@ -0,0 +1,170 @@
+package httputils
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ParameterParser ...
+//
+// It parses the value of a request parameter. The result should be assignable
+// to the type the parser is registered for.
+//
+type ParameterParser func(value string) (interface{}, error)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+	parameterParsers     = make(map[reflect.Type]ParameterParser)
+	parameterParsersLock sync.RWMutex
+)
+
+// RegisterParameterParser ...
+//
+// It registers the parser for the type, e.g.:
+//
+//	RegisterParameterParser(
+//		reflect.TypeOf(uuid.UUID{}),
+//		func(value string) (interface{}, error) { return uuid.Parse(value) },
+//	)
+//
+// A registered parser takes precedence over the other ways of the parsing
+// (see the ParsePathParameter() and BindRequest() functions). A repeated
+// registration replaces the parser; a nil parser removes the registration.
+//
+// It's safe for concurrent use, but parsers are usually registered
+// on the initialization of the program.
+//
+func RegisterParameterParser(dataType reflect.Type, parser ParameterParser) {
+	parameterParsersLock.Lock()
+	defer parameterParsersLock.Unlock()
+
+	if parser == nil {
+		delete(parameterParsers, dataType)
+		return
+	}
+
+	parameterParsers[dataType] = parser
+}
+
+func lookupParameterParser(dataType reflect.Type) (ParameterParser, bool) {
+	parameterParsersLock.RLock()
+	defer parameterParsersLock.RUnlock()
+
+	parser, ok := parameterParsers[dataType]
+	return parser, ok
+}
+
+// it parses the value in the following order: via the registered parser,
+// via the encoding.TextUnmarshaler interface, as the time.Duration type
+// and as the basic types; any trailing input is an error
+func parseParameterValue(value string, target reflect.Value) error {
+	if parser, ok := lookupParameterParser(target.Type()); ok {
+		parsedValue, err := parser(value)
+		if err != nil {
+			return err
+		}
+
+		parsedValueReflection := reflect.ValueOf(parsedValue)
+		if !parsedValueReflection.IsValid() ||
+			!parsedValueReflection.Type().AssignableTo(target.Type()) {
+			return errors.Errorf(
+				"the parser for the %s type has returned a value of the %T type",
+				target.Type(),
+				parsedValue,
+			)
+		}
+
+		target.Set(parsedValueReflection)
+		return nil
+	}
+
+	if target.Kind() == reflect.Ptr {
+		pointer := reflect.New(target.Type().Elem())
+		if err := parseParameterValue(value, pointer.Elem()); err != nil {
+			return err
+		}
+
+		target.Set(pointer)
+		return nil
+	}
+
+	if target.CanAddr() {
+		textUnmarshaler, ok := target.Addr().Interface().(encoding.TextUnmarshaler)
+		if ok {
+			return textUnmarshaler.UnmarshalText([]byte(value))
+		}
+	}
+
+	if target.Type() == durationType {
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+
+		target.SetInt(int64(duration))
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.String:
+		target.SetString(value)
+	case reflect.Bool:
+		parsedValue, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+
+		target.SetBool(parsedValue)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsedValue, err := strconv.ParseInt(value, 10, target.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		target.SetInt(parsedValue)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		parsedValue, err := strconv.ParseUint(value, 10, target.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		target.SetUint(parsedValue)
+	case reflect.Float32, reflect.Float64:
+		parsedValue, err := strconv.ParseFloat(value, target.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		target.SetFloat(parsedValue)
+	default:
+		return errors.Wrapf(ErrUnsupportedType, "the %s type", target.Type())
+	}
+
+	return nil
+}
+
+// it's an analog of the fmt.Sscan() function that requires the whole value
+// to be consumed; the target is changed only on success
+func scanParameterValueStrictly(value string, target reflect.Value) error {
+	reader := strings.NewReader(value)
+	scannedValue := reflect.New(target.Type())
+	if _, err := fmt.Fscan(reader, scannedValue.Interface()); err != nil {
+		return errors.Wrap(err, "unable to scan the data")
+	}
+	if reader.Len() != 0 {
+		return errors.Errorf("the value %q has unconsumed trailing input", value)
+	}
+
+	target.Set(scannedValue.Elem())
+	return nil
+}
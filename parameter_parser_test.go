@@ -0,0 +1,152 @@
+package httputils
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type testParameterID int
+
+func parseTestParameterID(value string) (interface{}, error) {
+	if !strings.HasPrefix(value, "id-") {
+		return nil, errors.New("the prefix is missing")
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(value, "id-"))
+	if err != nil {
+		return nil, err
+	}
+
+	return testParameterID(id), nil
+}
+
+func TestRegisterParameterParser(test *testing.T) {
+	dataType := reflect.TypeOf(testParameterID(0))
+	defer RegisterParameterParser(dataType, nil)
+
+	RegisterParameterParser(dataType, parseTestParameterID)
+	_, ok := lookupParameterParser(dataType)
+	assert.True(test, ok)
+
+	RegisterParameterParser(dataType, nil)
+	_, ok = lookupParameterParser(dataType)
+	assert.False(test, ok)
+}
+
+func TestParseParameterValue(test *testing.T) {
+	RegisterParameterParser(
+		reflect.TypeOf(testParameterID(0)),
+		parseTestParameterID,
+	)
+	RegisterParameterParser(
+		reflect.TypeOf(float32(0)),
+		func(value string) (interface{}, error) { return value, nil },
+	)
+	defer RegisterParameterParser(reflect.TypeOf(testParameterID(0)), nil)
+	defer RegisterParameterParser(reflect.TypeOf(float32(0)), nil)
+
+	type args struct {
+		value  string
+		target interface{}
+	}
+
+	for _, data := range []struct {
+		name       string
+		args       args
+		wantTarget interface{}
+		wantErr    string
+	}{
+		{
+			name: "success with the registered parser",
+			args: args{
+				value:  "id-23",
+				target: new(testParameterID),
+			},
+			wantTarget: func() *testParameterID {
+				id := testParameterID(23)
+				return &id
+			}(),
+			wantErr: "",
+		},
+		{
+			name: "success with the registered parser and a pointer",
+			args: args{
+				value:  "id-23",
+				target: new(*testParameterID),
+			},
+			wantTarget: func() **testParameterID {
+				id := testParameterID(23)
+				idPointer := &id
+				return &idPointer
+			}(),
+			wantErr: "",
+		},
+		{
+			name: "success with the time.Duration type",
+			args: args{
+				value:  "1m30s",
+				target: new(time.Duration),
+			},
+			wantTarget: func() *time.Duration {
+				duration := 90 * time.Second
+				return &duration
+			}(),
+			wantErr: "",
+		},
+		{
+			name: "error with the trailing input",
+			args: args{
+				value:  "12abc",
+				target: new(int64),
+			},
+			wantTarget: new(int64),
+			wantErr:    `strconv.ParseInt: parsing "12abc": invalid syntax`,
+		},
+		{
+			name: "error with the registered parser",
+			args: args{
+				value:  "23",
+				target: new(testParameterID),
+			},
+			wantTarget: new(testParameterID),
+			wantErr:    "the prefix is missing",
+		},
+		{
+			name: "error with the result of the registered parser",
+			args: args{
+				value:  "2.3",
+				target: new(float32),
+			},
+			wantTarget: new(float32),
+			wantErr: "the parser for the float32 type " +
+				"has returned a value of the string type",
+		},
+		{
+			name: "error with the unsupported type",
+			args: args{
+				value:  "test",
+				target: new(complex64),
+			},
+			wantTarget: new(complex64),
+			wantErr:    "the complex64 type: the type is unsupported",
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			target := reflect.ValueOf(data.args.target).Elem()
+			gotErr := parseParameterValue(data.args.value, target)
+
+			assert.Equal(test, data.wantTarget, data.args.target)
+			if data.wantErr == "" {
+				assert.NoError(test, gotErr)
+			} else {
+				assert.EqualError(test, gotErr, data.wantErr)
+			}
+		})
+	}
+}
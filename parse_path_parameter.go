@@ -1,43 +1,89 @@
 package httputils
 
 import (
-	"fmt"
 	"net/http"
 	"reflect"
 
-	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 )
 
+const pathParameterSource = "path"
+
+// PathParameterOption ...
+//
+// It sets an optional parameter of the ParsePathParameter() function.
+//
+type PathParameterOption func(options *pathParameterOptions)
+
+type pathParameterOptions struct {
+	source PathParameterSource
+}
+
+// WithPathParameterSource ...
+//
+// It sets the source of path parameters. By default, it's
+// the MuxPathParameterSource variable.
+//
+func WithPathParameterSource(source PathParameterSource) PathParameterOption {
+	return func(options *pathParameterOptions) {
+		options.source = source
+	}
+}
+
 // ParsePathParameter ...
 //
 // It extracts the parameter with the specified name from the path part
-// of the request URL and then scans it into the data.
+// of the request URL and then parses it into the data.
 //
 // The extracting does not actually work with the request URL directly. Instead,
-// this function relies on the use of the github.com/gorilla/mux package
-// for routing.
+// this function relies on the router that has matched the request (see
+// the PathParameterSource interface). By default, it's
+// the github.com/gorilla/mux package; see the WithPathParameterSource()
+// option.
+//
+// The parsing uses the parser registered for the type of the data (see
+// the RegisterParameterParser() function), the encoding.TextUnmarshaler
+// interface (e.g. for the time.Time type) or the strict parsing of the basic
+// types (strings are taken as is). Other types are scanned via the fmt.Sscan()
+// function and have the corresponding restrictions. In all cases, the whole
+// value should be consumed, so e.g. "12abc" isn't a valid integer.
 //
-// The scanning works via the fmt.Sscan() function and has the corresponding
-// restrictions.
+// Errors of the parsing are returned as the ParameterError error, so they are
+// resolved to the http.StatusBadRequest status code (see
+// the ResolveHTTPError() function). It's also the case for a missing
+// parameter, which is annotated with the ErrMissingParameter error.
 //
 func ParsePathParameter(
 	request *http.Request,
 	name string,
 	data interface{},
+	options ...PathParameterOption,
 ) error {
+	pathParameterOptions := pathParameterOptions{source: MuxPathParameterSource}
+	for _, option := range options {
+		option(&pathParameterOptions)
+	}
+
 	dataReflection := reflect.ValueOf(data)
 	if dataReflection.Kind() != reflect.Ptr || dataReflection.IsNil() {
 		return errors.New("the data is incorrect: it should be a non-nil pointer")
 	}
 
-	value, ok := mux.Vars(request)[name]
+	value, ok := pathParameterOptions.source.PathParameter(request, name)
 	if !ok {
-		return errors.New("the parameter is missing")
+		return ParameterError{
+			Source: pathParameterSource,
+			Name:   name,
+			Err:    ErrMissingParameter,
+		}
 	}
 
-	if _, err := fmt.Sscan(value, data); err != nil {
-		return errors.Wrap(err, "unable to scan the data")
+	err := parseParameterValue(value, dataReflection.Elem())
+	if errors.Is(err, ErrUnsupportedType) {
+		err = scanParameterValueStrictly(value, dataReflection.Elem())
+	}
+	if err != nil {
+		return ParameterError{Source: pathParameterSource, Name: name, Err: err}
 	}
 
 	return nil
@@ -3,18 +3,28 @@ package httputils
 import (
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/AlekSi/pointer"
 	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestParsePathParameter(test *testing.T) {
+	RegisterParameterParser(
+		reflect.TypeOf(testParameterID(0)),
+		parseTestParameterID,
+	)
+	defer RegisterParameterParser(reflect.TypeOf(testParameterID(0)), nil)
+
 	type args struct {
 		request *http.Request
 		name    string
 		data    interface{}
+		options []PathParameterOption
 	}
 
 	for _, data := range []struct {
@@ -49,6 +59,80 @@ func TestParsePathParameter(test *testing.T) {
 			wantData: pointer.ToString("data"),
 			wantErr:  assert.NoError,
 		},
+		{
+			name: "success with the path parameter source",
+			args: args{
+				request: httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
+				name:    "test",
+				data:    pointer.ToInt(0),
+				options: []PathParameterOption{
+					WithPathParameterSource(MapPathParameterSource{"test": "23"}),
+				},
+			},
+			wantData: pointer.ToInt(23),
+			wantErr:  assert.NoError,
+		},
+		{
+			name: "success with a string with spaces",
+			args: args{
+				request: mux.SetURLVars(
+					httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
+					map[string]string{"test": "one two"},
+				),
+				name: "test",
+				data: pointer.ToString(""),
+			},
+			wantData: pointer.ToString("one two"),
+			wantErr:  assert.NoError,
+		},
+		{
+			name: "success with the encoding.TextUnmarshaler interface",
+			args: args{
+				request: mux.SetURLVars(
+					httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
+					map[string]string{"test": "2006-01-02T15:04:05Z"},
+				),
+				name: "test",
+				data: &time.Time{},
+			},
+			wantData: func() *time.Time {
+				timestamp := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+				return &timestamp
+			}(),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success with the registered parser",
+			args: args{
+				request: mux.SetURLVars(
+					httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
+					map[string]string{"test": "id-23"},
+				),
+				name: "test",
+				data: func() *testParameterID {
+					var id testParameterID
+					return &id
+				}(),
+			},
+			wantData: func() *testParameterID {
+				id := testParameterID(23)
+				return &id
+			}(),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success with the fallback to scanning",
+			args: args{
+				request: mux.SetURLVars(
+					httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
+					map[string]string{"test": "(1+2i)"},
+				),
+				name: "test",
+				data: new(complex128),
+			},
+			wantData: func() *complex128 { value := 1 + 2i; return &value }(),
+			wantErr:  assert.NoError,
+		},
 		{
 			name: "error with a nil pointer",
 			args: args{
@@ -83,7 +167,25 @@ func TestParsePathParameter(test *testing.T) {
 				data:    pointer.ToInt(0),
 			},
 			wantData: pointer.ToInt(0),
-			wantErr:  assert.Error,
+			wantErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...interface{},
+			) bool {
+				wantErr := `path parameter "test": the parameter is missing`
+				return assert.EqualError(test, err, wantErr, msgAndArgs...) &&
+					assert.True(
+						test,
+						errors.Is(err, ErrMissingParameter),
+						msgAndArgs...,
+					) &&
+					assert.Equal(
+						test,
+						http.StatusBadRequest,
+						ResolveHTTPError(err).StatusCode(),
+						msgAndArgs...,
+					)
+			},
 		},
 		{
 			name: "error with the data scanning",
@@ -98,12 +200,93 @@ func TestParsePathParameter(test *testing.T) {
 			wantData: pointer.ToInt(0),
 			wantErr:  assert.Error,
 		},
+		{
+			name: "error with the trailing input",
+			args: args{
+				request: mux.SetURLVars(
+					httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
+					map[string]string{"test": "12abc"},
+				),
+				name: "test",
+				data: pointer.ToInt(0),
+			},
+			wantData: pointer.ToInt(0),
+			wantErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...interface{},
+			) bool {
+				wantErr := `path parameter "test": strconv.ParseInt: ` +
+					`parsing "12abc": invalid syntax`
+				return assert.EqualError(test, err, wantErr, msgAndArgs...) &&
+					assert.Equal(
+						test,
+						http.StatusBadRequest,
+						ResolveHTTPError(err).StatusCode(),
+						msgAndArgs...,
+					)
+			},
+		},
+		{
+			name: "error with the trailing input and the fallback to scanning",
+			args: args{
+				request: mux.SetURLVars(
+					httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
+					map[string]string{"test": "(1+2i)abc"},
+				),
+				name: "test",
+				data: new(complex128),
+			},
+			wantData: new(complex128),
+			wantErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...interface{},
+			) bool {
+				wantErr := `path parameter "test": ` +
+					`the value "(1+2i)abc" has unconsumed trailing input`
+				return assert.EqualError(test, err, wantErr, msgAndArgs...)
+			},
+		},
+		{
+			name: "error with the encoding.TextUnmarshaler interface",
+			args: args{
+				request: mux.SetURLVars(
+					httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
+					map[string]string{"test": "incorrect"},
+				),
+				name: "test",
+				data: &time.Time{},
+			},
+			wantData: &time.Time{},
+			wantErr:  assert.Error,
+		},
+		{
+			name: "error with the registered parser",
+			args: args{
+				request: mux.SetURLVars(
+					httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
+					map[string]string{"test": "23"},
+				),
+				name: "test",
+				data: func() *testParameterID {
+					var id testParameterID
+					return &id
+				}(),
+			},
+			wantData: func() *testParameterID {
+				var id testParameterID
+				return &id
+			}(),
+			wantErr: assert.Error,
+		},
 	} {
 		test.Run(data.name, func(test *testing.T) {
 			gotErr := ParsePathParameter(
 				data.args.request,
 				data.args.name,
 				data.args.data,
+				data.args.options...,
 			)
 
 			assert.Equal(test, data.wantData, data.args.data)
@@ -0,0 +1,167 @@
+package httputils
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// ErrMissingParameter ...
+//
+// It's returned (in a wrapped form) when a required parameter is missing.
+// See the ParameterError type.
+//
+var ErrMissingParameter = errors.New("the parameter is missing")
+
+const queryParameterSource = "query"
+
+// ParameterError ...
+//
+// It describes a problem with the request parameter with the specified name.
+// The optional source describes where the parameter is taken from
+// (e.g. "query" or "header"). It's resolved to the http.StatusBadRequest
+// status code (see the ResolveHTTPError() function).
+//
+type ParameterError struct {
+	Source string
+	Name   string
+	Err    error
+}
+
+// QueryParameterOption ...
+//
+// It sets an optional parameter of the ParseQueryParameter() function.
+//
+type QueryParameterOption func(options *queryParameterOptions)
+
+type queryParameterOptions struct {
+	required        bool
+	defaultValue    string
+	hasDefaultValue bool
+}
+
+// WithRequiredParameter ...
+//
+// It makes the parameter required: if it's missing, the ErrMissingParameter
+// error is returned (in a wrapped form).
+//
+func WithRequiredParameter() QueryParameterOption {
+	return func(options *queryParameterOptions) {
+		options.required = true
+	}
+}
+
+// WithDefaultValue ...
+//
+// It sets the value that is parsed into the data if the parameter
+// is missing.
+//
+func WithDefaultValue(defaultValue string) QueryParameterOption {
+	return func(options *queryParameterOptions) {
+		options.defaultValue = defaultValue
+		options.hasDefaultValue = true
+	}
+}
+
+// ParseQueryParameter ...
+//
+// It extracts the parameter with the specified name from the query part
+// of the request URL and then parses it into the data. The data should be
+// a non-nil pointer.
+//
+// By default, the parameter is optional: if it's missing, the data stays
+// unchanged (so it may hold the default value); see also
+// the WithRequiredParameter() and WithDefaultValue() options.
+//
+// If the data is a pointer to a slice (except a byte slice), all values
+// of the repeated parameter are parsed into its elements. Otherwise, only
+// the first value is used.
+//
+// The parsing works like in the ParsePathParameter() function, i.e. it uses
+// the registered parsers, the encoding.TextUnmarshaler interface
+// or the strict parsing of the basic types, so e.g. "12abc" isn't a valid
+// integer. The data is changed only on success.
+//
+// Errors related to the parameter are returned as the ParameterError error,
+// so they are resolved to the http.StatusBadRequest status code (see
+// the ResolveHTTPError() function).
+//
+func ParseQueryParameter(
+	request *http.Request,
+	name string,
+	data interface{},
+	options ...QueryParameterOption,
+) error {
+	var queryParameterOptions queryParameterOptions
+	for _, option := range options {
+		option(&queryParameterOptions)
+	}
+
+	dataReflection := reflect.ValueOf(data)
+	if dataReflection.Kind() != reflect.Ptr || dataReflection.IsNil() {
+		return errors.New("the data is incorrect: it should be a non-nil pointer")
+	}
+
+	values, ok := request.URL.Query()[name]
+	if !ok || len(values) == 0 {
+		switch {
+		case queryParameterOptions.required:
+			return ParameterError{
+				Source: queryParameterSource,
+				Name:   name,
+				Err:    ErrMissingParameter,
+			}
+		case queryParameterOptions.hasDefaultValue:
+			values = []string{queryParameterOptions.defaultValue}
+		default:
+			return nil
+		}
+	}
+
+	err := parseParameterValues(values, dataReflection.Elem())
+	if errors.Is(err, ErrUnsupportedType) &&
+		!isParameterSlice(dataReflection.Elem().Type()) {
+		err = scanParameterValueStrictly(values[0], dataReflection.Elem())
+	}
+	if err != nil {
+		return ParameterError{Source: queryParameterSource, Name: name, Err: err}
+	}
+
+	return nil
+}
+
+func (err ParameterError) Error() string {
+	if err.Source == "" {
+		return fmt.Sprintf("parameter %q: %v", err.Name, err.Err)
+	}
+
+	return fmt.Sprintf("%s parameter %q: %v", err.Source, err.Name, err.Err)
+}
+
+// StatusCode ...
+//
+// It returns the http.StatusBadRequest status code. It implements
+// the StatusCodeProvider interface.
+//
+func (err ParameterError) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+// Cause ...
+//
+// It returns the annotated error. It's used by the errors.Cause() function.
+//
+func (err ParameterError) Cause() error {
+	return err.Err
+}
+
+// Unwrap ...
+//
+// It returns the annotated error. It's used by the errors.Is()
+// and errors.As() functions.
+//
+func (err ParameterError) Unwrap() error {
+	return err.Err
+}
@@ -0,0 +1,209 @@
+package httputils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQueryParameter(test *testing.T) {
+	type args struct {
+		request *http.Request
+		name    string
+		data    interface{}
+		options []QueryParameterOption
+	}
+
+	newRequest := func(query string) *http.Request {
+		return httptest.NewRequest(http.MethodGet, "http://example.com/?"+query, nil)
+	}
+
+	for _, data := range []struct {
+		name           string
+		args           args
+		wantData       interface{}
+		wantErr        string
+		wantStatusCode int
+	}{
+		{
+			name: "success with an integer",
+			args: args{
+				request: newRequest("test=23&test=42"),
+				name:    "test",
+				data:    pointer.ToInt(0),
+				options: nil,
+			},
+			wantData: pointer.ToInt(23),
+			wantErr:  "",
+		},
+		{
+			name: "success with a string",
+			args: args{
+				request: newRequest("test=one+two"),
+				name:    "test",
+				data:    pointer.ToString(""),
+				options: nil,
+			},
+			wantData: pointer.ToString("one two"),
+			wantErr:  "",
+		},
+		{
+			name: "success with a slice",
+			args: args{
+				request: newRequest("test=23&other=5&test=42"),
+				name:    "test",
+				data:    &[]int{100},
+				options: nil,
+			},
+			wantData: &[]int{23, 42},
+			wantErr:  "",
+		},
+		{
+			name: "success with the encoding.TextUnmarshaler interface",
+			args: args{
+				request: newRequest("test=2006-01-02T15:04:05Z"),
+				name:    "test",
+				data:    &time.Time{},
+				options: nil,
+			},
+			wantData: pointer.ToTime(time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)),
+			wantErr:  "",
+		},
+		{
+			name: "success with a duration",
+			args: args{
+				request: newRequest("test=1m30s"),
+				name:    "test",
+				data:    pointer.ToDuration(0),
+				options: nil,
+			},
+			wantData: pointer.ToDuration(90 * time.Second),
+			wantErr:  "",
+		},
+		{
+			name: "success with the missing optional parameter",
+			args: args{
+				request: newRequest("other=5"),
+				name:    "test",
+				data:    pointer.ToInt(100),
+				options: nil,
+			},
+			wantData: pointer.ToInt(100),
+			wantErr:  "",
+		},
+		{
+			name: "success with the default value",
+			args: args{
+				request: newRequest("other=5"),
+				name:    "test",
+				data:    &[]int{100},
+				options: []QueryParameterOption{WithDefaultValue("23")},
+			},
+			wantData: &[]int{23},
+			wantErr:  "",
+		},
+		{
+			name: "error with a nil pointer",
+			args: args{
+				request: newRequest("test=23"),
+				name:    "test",
+				data:    (*int)(nil),
+				options: nil,
+			},
+			wantData:       (*int)(nil),
+			wantErr:        "the data is incorrect: it should be a non-nil pointer",
+			wantStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name: "error with the missing required parameter",
+			args: args{
+				request: newRequest("other=5"),
+				name:    "test",
+				data:    pointer.ToInt(100),
+				options: []QueryParameterOption{
+					WithRequiredParameter(),
+					WithDefaultValue("23"),
+				},
+			},
+			wantData:       pointer.ToInt(100),
+			wantErr:        `query parameter "test": the parameter is missing`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "error with the data scanning",
+			args: args{
+				request: newRequest("test=incorrect"),
+				name:    "test",
+				data:    pointer.ToInt(100),
+				options: nil,
+			},
+			wantData: pointer.ToInt(100),
+			wantErr: `query parameter "test": ` +
+				`strconv.ParseInt: parsing "incorrect": invalid syntax`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "error with the trailing input",
+			args: args{
+				request: newRequest("test=12abc"),
+				name:    "test",
+				data:    pointer.ToInt(100),
+				options: nil,
+			},
+			wantData: pointer.ToInt(100),
+			wantErr: `query parameter "test": ` +
+				`strconv.ParseInt: parsing "12abc": invalid syntax`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "error with the data scanning in a slice",
+			args: args{
+				request: newRequest("test=23&test=incorrect"),
+				name:    "test",
+				data:    &[]int{100},
+				options: nil,
+			},
+			wantData: &[]int{100},
+			wantErr: `query parameter "test": unable to parse the value #1: ` +
+				`strconv.ParseInt: parsing "incorrect": invalid syntax`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			gotErr := ParseQueryParameter(
+				data.args.request,
+				data.args.name,
+				data.args.data,
+				data.args.options...,
+			)
+
+			assert.Equal(test, data.wantData, data.args.data)
+			if data.wantErr == "" {
+				assert.NoError(test, gotErr)
+				return
+			}
+
+			assert.EqualError(test, gotErr, data.wantErr)
+			gotStatusCode := ResolveHTTPError(gotErr).StatusCode()
+			assert.Equal(test, data.wantStatusCode, gotStatusCode)
+		})
+	}
+}
+
+func TestParameterError(test *testing.T) {
+	err := errors.Wrap(
+		ParameterError{Name: "test", Err: ErrMissingParameter},
+		"dummy",
+	)
+
+	var parameterErr ParameterError
+	assert.True(test, errors.As(err, &parameterErr))
+	assert.Equal(test, "test", parameterErr.Name)
+	assert.True(test, errors.Is(err, ErrMissingParameter))
+	assert.Equal(test, ErrMissingParameter, errors.Cause(err))
+}
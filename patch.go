@@ -0,0 +1,818 @@
+package httputils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MergePatchContentType ...
+//
+// It's the media type of JSON Merge Patch defined in RFC 7396.
+//
+const MergePatchContentType = "application/merge-patch+json"
+
+// JSONPatchContentType ...
+//
+// It's the media type of JSON Patch defined in RFC 6902.
+//
+const JSONPatchContentType = "application/json-patch+json"
+
+// ErrTestFailed ...
+//
+// It's returned (in a wrapped form) by the ApplyJSONPatch() function
+// when the test operation failed. See the PatchError type.
+//
+var ErrTestFailed = errors.New("the test operation failed")
+
+// ErrInvalidPath ...
+//
+// It's returned (in a wrapped form) by the ApplyJSONPatch() function
+// when the path is malformed or doesn't exist. See the PatchError type.
+//
+var ErrInvalidPath = errors.New("the path is invalid")
+
+// ErrInvalidOperation ...
+//
+// It's returned (in a wrapped form) by the ApplyJSONPatch() function
+// when the operation is malformed. See the PatchError type.
+//
+var ErrInvalidOperation = errors.New("the operation is invalid")
+
+// PatchError ...
+//
+// It describes the failed operation of JSON Patch.
+//
+// It's resolved to the status code according to RFC 5789 (see
+// the ResolveHTTPError() function): http.StatusConflict for the ErrTestFailed
+// error, http.StatusBadRequest for the ErrInvalidOperation error
+// and http.StatusUnprocessableEntity for other errors.
+//
+type PatchError struct {
+	OperationIndex int
+	Operation      string
+	Path           string
+	Err            error
+}
+
+var (
+	// the order of the replacements is defined in RFC 6901
+	jsonPointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+type jsonPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// ApplyMergePatch ...
+//
+// It applies the patch to the document according to RFC 7396.
+// Both of them should be JSON.
+//
+func ApplyMergePatch(document []byte, patch []byte) ([]byte, error) {
+	documentValue, err := decodeJSONValue(document)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to decode the document")
+	}
+
+	patchValue, err := decodeJSONValue(patch)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to decode the patch")
+	}
+
+	result, err := json.Marshal(mergePatch(documentValue, patchValue))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to encode the document")
+	}
+
+	return result, nil
+}
+
+// ApplyJSONPatch ...
+//
+// It applies the patch to the document according to RFC 6902.
+// Both of them should be JSON.
+//
+// The operations are applied in order. An error of an operation is returned
+// as the PatchError error (in a wrapped form).
+//
+func ApplyJSONPatch(document []byte, patch []byte) ([]byte, error) {
+	documentValue, err := decodeJSONValue(document)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to decode the document")
+	}
+
+	var operations []jsonPatchOperation
+	if err := json.Unmarshal(patch, &operations); err != nil {
+		return nil, errors.Wrap(BadRequest(err), "unable to decode the patch")
+	}
+
+	for index, operation := range operations {
+		documentValue, err = applyJSONPatchOperation(documentValue, operation)
+		if err != nil {
+			return nil, PatchError{
+				OperationIndex: index,
+				Operation:      operation.Op,
+				Path:           operation.Path,
+				Err:            err,
+			}
+		}
+	}
+
+	result, err := json.Marshal(documentValue)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to encode the document")
+	}
+
+	return result, nil
+}
+
+// ReadMergePatchRequest ...
+//
+// It reads JSON Merge Patch from the request (see the ReadJSONRequest()
+// function), applies it to the JSON representation of the target
+// and then unmarshals the result into the target (see
+// the ReadJSONWithOptions() function). The target should be a non-nil
+// pointer holding the current state of a resource.
+//
+// The result is unmarshalled into a copy of the target, where the fields
+// visible in JSON are reset, so that fields removed by the patch don't stay.
+// Fields hidden from JSON (i.e. unexported ones and ones with the "-" tag)
+// are kept as is, including ones of nested structures. Pointers to nested
+// structures without such fields are reset to nil.
+//
+// The content type of the request should be the MergePatchContentType
+// constant. The options are applied as follows: the maximal size limits
+// only the patch, while the other options (including the validation) affect
+// only the result.
+//
+// The copy replaces the target only on success, so on an error the target
+// stays unchanged.
+//
+func ReadMergePatchRequest(
+	writer http.ResponseWriter,
+	request *http.Request,
+	target interface{},
+	options ...ReadJSONOption,
+) error {
+	return readPatchRequest(
+		writer,
+		request,
+		target,
+		MergePatchContentType,
+		ApplyMergePatch,
+		options,
+	)
+}
+
+// ReadJSONPatchRequest ...
+//
+// It's an analog of the ReadMergePatchRequest() function for JSON Patch
+// (see the ApplyJSONPatch() function). The content type of the request
+// should be the JSONPatchContentType constant.
+//
+func ReadJSONPatchRequest(
+	writer http.ResponseWriter,
+	request *http.Request,
+	target interface{},
+	options ...ReadJSONOption,
+) error {
+	return readPatchRequest(
+		writer,
+		request,
+		target,
+		JSONPatchContentType,
+		ApplyJSONPatch,
+		options,
+	)
+}
+
+func (err PatchError) Error() string {
+	return fmt.Sprintf(
+		"operation #%d (%s %q): %v",
+		err.OperationIndex,
+		err.Operation,
+		err.Path,
+		err.Err,
+	)
+}
+
+// StatusCode ...
+//
+// It returns the status code according to the annotated error. It implements
+// the StatusCodeProvider interface.
+//
+func (err PatchError) StatusCode() int {
+	switch {
+	case errors.Is(err.Err, ErrTestFailed):
+		return http.StatusConflict
+	case errors.Is(err.Err, ErrInvalidOperation):
+		return http.StatusBadRequest
+	default:
+		return http.StatusUnprocessableEntity
+	}
+}
+
+// Cause ...
+//
+// It returns the annotated error. It's used by the errors.Cause() function.
+//
+func (err PatchError) Cause() error {
+	return err.Err
+}
+
+// Unwrap ...
+//
+// It returns the annotated error. It's used by the errors.Is()
+// and errors.As() functions.
+//
+func (err PatchError) Unwrap() error {
+	return err.Err
+}
+
+func readPatchRequest(
+	writer http.ResponseWriter,
+	request *http.Request,
+	target interface{},
+	patchContentType string,
+	applier func(document []byte, patch []byte) ([]byte, error),
+	options []ReadJSONOption,
+) error {
+	targetReflection := reflect.ValueOf(target)
+	if targetReflection.Kind() != reflect.Ptr || targetReflection.IsNil() {
+		return errors.New("the target is incorrect: it should be a non-nil pointer")
+	}
+
+	contentType := request.Header.Get("Content-Type")
+	err := checkPatchContentType(contentType, patchContentType)
+	if err != nil {
+		return errors.Wrap(err, "unable to read the request")
+	}
+
+	// the validation is intended for the result, not for the patch
+	patchOptions := appendReadJSONOptions(options, WithValidator(nil))
+	var patch json.RawMessage
+	err = ReadJSONRequest(writer, request, &patch, patchOptions...)
+	if err != nil {
+		return err
+	}
+
+	document, err := json.Marshal(target)
+	if err != nil {
+		return errors.Wrap(err, "unable to encode the target")
+	}
+
+	result, err := applier(document, patch)
+	if err != nil {
+		return errors.Wrap(err, "unable to apply the patch")
+	}
+
+	// the result is decoded into a reset copy of the target, so that removed
+	// fields don't stay and the target isn't changed on an error
+	resultTarget := reflect.New(targetReflection.Elem().Type())
+	resultTarget.Elem().Set(targetReflection.Elem())
+	resetJSONFields(resultTarget.Elem())
+
+	// the maximal size is intended for the patch, not for the result
+	resultOptions := appendReadJSONOptions(options, WithMaxSize(0))
+	err = ReadJSONWithOptions(
+		bytes.NewReader(result),
+		resultTarget.Interface(),
+		resultOptions...,
+	)
+	if err != nil {
+		return errors.Wrap(err, "unable to read the result")
+	}
+
+	targetReflection.Elem().Set(resultTarget.Elem())
+	return nil
+}
+
+// it zeroes the parts of the value that are visible to the encoding/json
+// package; structures with hidden fields (including ones behind pointers)
+// are processed recursively, so that these fields are kept, and ones behind
+// pointers are copied, so that the originals stay unchanged
+func resetJSONFields(value reflect.Value) {
+	switch {
+	case value.Kind() == reflect.Ptr && !value.IsNil() && value.CanSet() &&
+		hasHiddenJSONFields(value.Type(), make(map[reflect.Type]struct{})):
+		copiedValue := reflect.New(value.Type().Elem())
+		copiedValue.Elem().Set(value.Elem())
+		resetJSONFields(copiedValue.Elem())
+
+		value.Set(copiedValue)
+	case value.Kind() == reflect.Struct && isJSONStructure(value.Type()):
+		for index := 0; index < value.NumField(); index++ {
+			if isJSONField(value.Type().Field(index)) {
+				resetJSONFields(value.Field(index))
+			}
+		}
+	case value.CanSet():
+		value.Set(reflect.Zero(value.Type()))
+	}
+}
+
+// it checks that the structure (or the pointer to it) has fields hidden from
+// the encoding/json package, including ones of nested structures; the visited
+// types are tracked to stop on recursive types
+func hasHiddenJSONFields(
+	valueType reflect.Type,
+	visitedTypes map[reflect.Type]struct{},
+) bool {
+	if valueType.Kind() == reflect.Ptr {
+		valueType = valueType.Elem()
+	}
+	if !isJSONStructure(valueType) {
+		return false
+	}
+
+	if _, ok := visitedTypes[valueType]; ok {
+		return false
+	}
+	visitedTypes[valueType] = struct{}{}
+
+	for index := 0; index < valueType.NumField(); index++ {
+		field := valueType.Field(index)
+		if !isJSONField(field) || hasHiddenJSONFields(field.Type, visitedTypes) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// it checks that the field is visible to the encoding/json package
+func isJSONField(field reflect.StructField) bool {
+	if field.Tag.Get("json") == "-" {
+		return false
+	}
+
+	// fields of embedded structures are promoted even if their types
+	// are unexported
+	fieldType := field.Type
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	return field.IsExported() ||
+		(field.Anonymous && fieldType.Kind() == reflect.Struct)
+}
+
+// it checks that the type is a structure unmarshalled by fields,
+// i.e. without custom unmarshalling
+func isJSONStructure(valueType reflect.Type) bool {
+	if valueType.Kind() != reflect.Struct {
+		return false
+	}
+
+	pointerType := reflect.PtrTo(valueType)
+	return !pointerType.Implements(jsonUnmarshalerType) &&
+		!pointerType.Implements(textUnmarshalerType)
+}
+
+// it doesn't modify the original options
+func appendReadJSONOptions(
+	options []ReadJSONOption,
+	extraOptions ...ReadJSONOption,
+) []ReadJSONOption {
+	result := make([]ReadJSONOption, 0, len(options)+len(extraOptions))
+	return append(append(result, options...), extraOptions...)
+}
+
+func checkPatchContentType(
+	contentType string,
+	patchContentType string,
+) error {
+	if err := checkJSONContentType(contentType); err != nil {
+		return err
+	}
+
+	// the content type is already checked above
+	mediaType, _, _ := mime.ParseMediaType(contentType) // nolint: errcheck
+	if mediaType != patchContentType {
+		err := errors.Wrapf(
+			ErrUnsupportedMediaType,
+			"the content type %q isn't %q",
+			mediaType,
+			patchContentType,
+		)
+		return NewHTTPError(http.StatusUnsupportedMediaType, err)
+	}
+
+	return nil
+}
+
+func decodeJSONValue(data []byte) (interface{}, error) {
+	var value interface{}
+	err := ReadJSONWithOptions(
+		bytes.NewReader(data),
+		&value,
+		WithUseNumber(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func mergePatch(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = make(map[string]interface{})
+	}
+
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+
+		targetObject[name] = mergePatch(targetObject[name], value)
+	}
+
+	return targetObject
+}
+
+func applyJSONPatchOperation(
+	document interface{},
+	operation jsonPatchOperation,
+) (interface{}, error) {
+	path, err := parseJSONPointer(operation.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	switch operation.Op {
+	case "add", "replace", "test":
+		if len(operation.Value) == 0 {
+			return nil, errors.Wrap(ErrInvalidOperation, "the value is missing")
+		}
+
+		value, err = decodeJSONValue(operation.Value)
+		if err != nil {
+			return nil, errors.Wrapf(
+				ErrInvalidOperation,
+				"the value is incorrect: %v",
+				err,
+			)
+		}
+	}
+
+	switch operation.Op {
+	case "add":
+		return addJSONValue(document, path, value)
+	case "remove":
+		return removeJSONValue(document, path)
+	case "replace":
+		if _, err := getJSONValue(document, path); err != nil {
+			return nil, err
+		}
+
+		return setJSONValue(document, path, value)
+	case "move", "copy":
+		from, err := parseJSONPointer(operation.From)
+		if err != nil {
+			return nil, errors.Wrap(err, "the from path is incorrect")
+		}
+
+		value, err := getJSONValue(document, from)
+		if err != nil {
+			return nil, errors.Wrap(err, "the from path is incorrect")
+		}
+
+		if operation.Op == "move" {
+			if isJSONPointerPrefix(from, path) {
+				return nil, errors.Wrap(
+					ErrInvalidPath,
+					"the value can't be moved into one of its children",
+				)
+			}
+
+			if document, err = removeJSONValue(document, from); err != nil {
+				return nil, err
+			}
+		} else {
+			value = copyJSONValue(value)
+		}
+
+		return addJSONValue(document, path, value)
+	case "test":
+		currentValue, err := getJSONValue(document, path)
+		if err != nil {
+			return nil, err
+		}
+		if !isEqualJSONValue(currentValue, value) {
+			return nil, ErrTestFailed
+		}
+
+		return document, nil
+	default:
+		return nil, errors.Wrapf(
+			ErrInvalidOperation,
+			"the operation %q is unknown",
+			operation.Op,
+		)
+	}
+}
+
+// it parses the JSON Pointer defined in RFC 6901
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, errors.Wrapf(
+			ErrInvalidPath,
+			"the pointer %q doesn't start with a slash",
+			pointer,
+		)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for index, token := range tokens {
+		tokens[index] = jsonPointerUnescaper.Replace(token)
+	}
+
+	return tokens, nil
+}
+
+func isJSONPointerPrefix(prefix []string, path []string) bool {
+	if len(prefix) >= len(path) {
+		return false
+	}
+
+	for index, token := range prefix {
+		if path[index] != token {
+			return false
+		}
+	}
+
+	return true
+}
+
+func getJSONValue(document interface{}, path []string) (interface{}, error) {
+	value := document
+	for _, token := range path {
+		switch container := value.(type) {
+		case map[string]interface{}:
+			child, ok := container[token]
+			if !ok {
+				return nil, errors.Wrapf(ErrInvalidPath, "the key %q is missing", token)
+			}
+
+			value = child
+		case []interface{}:
+			index, err := parseJSONArrayIndex(token, len(container)-1)
+			if err != nil {
+				return nil, err
+			}
+
+			value = container[index]
+		default:
+			return nil, errors.Wrapf(
+				ErrInvalidPath,
+				"the parent of the %q key isn't a container",
+				token,
+			)
+		}
+	}
+
+	return value, nil
+}
+
+func addJSONValue(
+	document interface{},
+	path []string,
+	value interface{},
+) (interface{}, error) {
+	return updateJSONValue(document, path, value, func(
+		parent interface{},
+		token string,
+	) (interface{}, error) {
+		switch container := parent.(type) {
+		case map[string]interface{}:
+			container[token] = value
+			return container, nil
+		case []interface{}:
+			if token == "-" {
+				return append(container, value), nil
+			}
+
+			index, err := parseJSONArrayIndex(token, len(container))
+			if err != nil {
+				return nil, err
+			}
+
+			container = append(container, nil)
+			copy(container[index+1:], container[index:])
+			container[index] = value
+
+			return container, nil
+		default:
+			return nil, errors.Wrap(ErrInvalidPath, "the parent isn't a container")
+		}
+	})
+}
+
+func setJSONValue(
+	document interface{},
+	path []string,
+	value interface{},
+) (interface{}, error) {
+	return updateJSONValue(document, path, value, func(
+		parent interface{},
+		token string,
+	) (interface{}, error) {
+		switch container := parent.(type) {
+		case map[string]interface{}:
+			container[token] = value
+		case []interface{}:
+			// the index is already checked by the getJSONValue() function
+			index, _ := strconv.Atoi(token) // nolint: errcheck
+			container[index] = value
+		}
+
+		return parent, nil
+	})
+}
+
+func removeJSONValue(
+	document interface{},
+	path []string,
+) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, errors.Wrap(ErrInvalidPath, "the root can't be removed")
+	}
+
+	return updateJSONValue(document, path, nil, func(
+		parent interface{},
+		token string,
+	) (interface{}, error) {
+		switch container := parent.(type) {
+		case map[string]interface{}:
+			if _, ok := container[token]; !ok {
+				return nil, errors.Wrapf(ErrInvalidPath, "the key %q is missing", token)
+			}
+
+			delete(container, token)
+			return container, nil
+		case []interface{}:
+			index, err := parseJSONArrayIndex(token, len(container)-1)
+			if err != nil {
+				return nil, err
+			}
+
+			return append(container[:index], container[index+1:]...), nil
+		default:
+			return nil, errors.Wrap(ErrInvalidPath, "the parent isn't a container")
+		}
+	})
+}
+
+// it replaces the parent of the path with the result of the updater;
+// the root path means the replacement of the document with the value
+func updateJSONValue(
+	document interface{},
+	path []string,
+	value interface{},
+	updater func(parent interface{}, token string) (interface{}, error),
+) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parentPath, token := path[:len(path)-1], path[len(path)-1]
+	parent, err := getJSONValue(document, parentPath)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedParent, err := updater(parent, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(parentPath) == 0 {
+		return updatedParent, nil
+	}
+
+	// the array could be reallocated, so the parent should be replaced
+	return setJSONValue(document, parentPath, updatedParent)
+}
+
+func parseJSONArrayIndex(token string, maxIndex int) (int, error) {
+	if !isJSONArrayIndex(token) {
+		return 0, errors.Wrapf(ErrInvalidPath, "the index %q is incorrect", token)
+	}
+
+	index, err := strconv.Atoi(token)
+	if err != nil || index > maxIndex {
+		return 0, errors.Wrapf(ErrInvalidPath, "the index %q is incorrect", token)
+	}
+
+	return index, nil
+}
+
+// it checks that the token is an array index allowed by RFC 6901,
+// i.e. "0" or digits without a leading zero (without signs in particular)
+func isJSONArrayIndex(token string) bool {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return false
+	}
+
+	for index := 0; index < len(token); index++ {
+		if token[index] < '0' || token[index] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func copyJSONValue(value interface{}) interface{} {
+	switch container := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(container))
+		for key, child := range container {
+			result[key] = copyJSONValue(child)
+		}
+
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(container))
+		for index, child := range container {
+			result[index] = copyJSONValue(child)
+		}
+
+		return result
+	default:
+		return value
+	}
+}
+
+func isEqualJSONValue(value interface{}, other interface{}) bool {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		otherObject, ok := other.(map[string]interface{})
+		if !ok || len(value) != len(otherObject) {
+			return false
+		}
+
+		for key, child := range value {
+			otherChild, ok := otherObject[key]
+			if !ok || !isEqualJSONValue(child, otherChild) {
+				return false
+			}
+		}
+
+		return true
+	case []interface{}:
+		otherArray, ok := other.([]interface{})
+		if !ok || len(value) != len(otherArray) {
+			return false
+		}
+
+		for index, child := range value {
+			if !isEqualJSONValue(child, otherArray[index]) {
+				return false
+			}
+		}
+
+		return true
+	case json.Number:
+		otherNumber, ok := other.(json.Number)
+		if !ok {
+			return false
+		}
+
+		// numbers are equal if their values are equal (e.g. 1 and 1.0)
+		number, err := value.Float64()
+		otherFloat, otherErr := otherNumber.Float64()
+		if err != nil || otherErr != nil {
+			return value == otherNumber
+		}
+
+		return number == otherFloat
+	default:
+		return value == other
+	}
+}
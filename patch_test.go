@@ -0,0 +1,516 @@
+package httputils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyMergePatch(test *testing.T) {
+	type args struct {
+		document string
+		patch    string
+	}
+
+	for _, data := range []struct {
+		name         string
+		args         args
+		wantDocument string
+		wantErr      assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/RFC 7396 example",
+			args: args{
+				document: `{
+					"title": "Goodbye!",
+					"author": {"givenName": "John", "familyName": "Doe"},
+					"tags": ["example", "sample"],
+					"content": "This will be unchanged"
+				}`,
+				patch: `{
+					"title": "Hello!",
+					"phoneNumber": "+01-123-456-7890",
+					"author": {"familyName": null},
+					"tags": ["example"]
+				}`,
+			},
+			wantDocument: `{
+				"title": "Hello!",
+				"author": {"givenName": "John"},
+				"tags": ["example"],
+				"content": "This will be unchanged",
+				"phoneNumber": "+01-123-456-7890"
+			}`,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/non-object patch",
+			args: args{
+				document: `{"a": "b"}`,
+				patch:    `["c"]`,
+			},
+			wantDocument: `["c"]`,
+			wantErr:      assert.NoError,
+		},
+		{
+			name: "success/big numbers",
+			args: args{
+				document: `{"a": 9007199254740993}`,
+				patch:    `{"b": {"c": null, "d": 1}}`,
+			},
+			wantDocument: `{"a": 9007199254740993, "b": {"d": 1}}`,
+			wantErr:      assert.NoError,
+		},
+		{
+			name: "error with the document",
+			args: args{
+				document: `incorrect`,
+				patch:    `{}`,
+			},
+			wantDocument: "",
+			wantErr:      assert.Error,
+		},
+		{
+			name: "error with the patch",
+			args: args{
+				document: `{}`,
+				patch:    `incorrect`,
+			},
+			wantDocument: "",
+			wantErr:      assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			gotDocument, gotErr :=
+				ApplyMergePatch([]byte(data.args.document), []byte(data.args.patch))
+
+			if data.wantDocument != "" {
+				assert.JSONEq(test, data.wantDocument, string(gotDocument))
+				assert.NotContains(test, string(gotDocument), "e+")
+			} else {
+				assert.Nil(test, gotDocument)
+			}
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestApplyJSONPatch(test *testing.T) {
+	type args struct {
+		document string
+		patch    string
+	}
+
+	for _, data := range []struct {
+		name           string
+		args           args
+		wantDocument   string
+		wantErr        error
+		wantStatusCode int
+	}{
+		{
+			name: "success/add",
+			args: args{
+				document: `{"foo": ["bar", "baz"]}`,
+				patch: `[
+					{"op": "add", "path": "/baz", "value": "qux"},
+					{"op": "add", "path": "/foo/1", "value": "qux"},
+					{"op": "add", "path": "/foo/-", "value": null},
+					{"op": "add", "path": "/a~1b~0c", "value": {"d": 1}}
+				]`,
+			},
+			wantDocument: `{
+				"baz": "qux",
+				"foo": ["bar", "qux", "baz", null],
+				"a/b~c": {"d": 1}
+			}`,
+		},
+		{
+			name: "success/remove and replace",
+			args: args{
+				document: `{"foo": ["bar", "qux", "baz"], "baz": "qux"}`,
+				patch: `[
+					{"op": "remove", "path": "/foo/1"},
+					{"op": "replace", "path": "/baz", "value": "boo"},
+					{"op": "replace", "path": "/foo/0", "value": 1}
+				]`,
+			},
+			wantDocument: `{"foo": [1, "baz"], "baz": "boo"}`,
+		},
+		{
+			name: "success/move and copy",
+			args: args{
+				document: `{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": [1]}}`,
+				patch: `[
+					{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"},
+					{"op": "copy", "from": "/qux/corge", "path": "/foo/corge"},
+					{"op": "add", "path": "/foo/corge/-", "value": 2}
+				]`,
+			},
+			wantDocument: `{
+				"foo": {"bar": "baz", "corge": [1, 2]},
+				"qux": {"corge": [1], "thud": "fred"}
+			}`,
+		},
+		{
+			name: "success/test and the root",
+			args: args{
+				document: `{"baz": "qux", "foo": [1, 2.0, {"a": true}]}`,
+				patch: `[
+					{"op": "test", "path": "/foo", "value": [1.0, 2, {"a": true}]},
+					{"op": "test", "path": "", "value": {"foo": [1, 2, {"a": true}], "baz": "qux"}},
+					{"op": "replace", "path": "", "value": [1]}
+				]`,
+			},
+			wantDocument: `[1]`,
+		},
+		{
+			name: "error with the failed test",
+			args: args{
+				document: `{"baz": "qux"}`,
+				patch:    `[{"op": "test", "path": "/baz", "value": "bar"}]`,
+			},
+			wantErr:        ErrTestFailed,
+			wantStatusCode: http.StatusConflict,
+		},
+		{
+			name: "error with the missing path",
+			args: args{
+				document: `{"foo": ["bar"]}`,
+				patch: `[
+					{"op": "add", "path": "/baz", "value": "qux"},
+					{"op": "remove", "path": "/foo/1"}
+				]`,
+			},
+			wantErr:        ErrInvalidPath,
+			wantStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "error with the incorrect index",
+			args: args{
+				document: `{"foo": ["bar", "baz"]}`,
+				patch:    `[{"op": "remove", "path": "/foo/+1"}]`,
+			},
+			wantErr:        ErrInvalidPath,
+			wantStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "error with the index with the sign of zero",
+			args: args{
+				document: `{"foo": ["bar", "baz"]}`,
+				patch:    `[{"op": "remove", "path": "/foo/-0"}]`,
+			},
+			wantErr:        ErrInvalidPath,
+			wantStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "error with the index with the leading zero",
+			args: args{
+				document: `{"foo": ["bar", "baz"]}`,
+				patch:    `[{"op": "remove", "path": "/foo/01"}]`,
+			},
+			wantErr:        ErrInvalidPath,
+			wantStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "error with the incorrect path",
+			args: args{
+				document: `{"foo": ["bar"]}`,
+				patch:    `[{"op": "add", "path": "foo", "value": "qux"}]`,
+			},
+			wantErr:        ErrInvalidPath,
+			wantStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "error with the move into a child",
+			args: args{
+				document: `{"foo": {"bar": 1}}`,
+				patch:    `[{"op": "move", "from": "/foo", "path": "/foo/bar"}]`,
+			},
+			wantErr:        ErrInvalidPath,
+			wantStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "error with the missing value",
+			args: args{
+				document: `{"foo": "bar"}`,
+				patch:    `[{"op": "replace", "path": "/foo"}]`,
+			},
+			wantErr:        ErrInvalidOperation,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "error with the unknown operation",
+			args: args{
+				document: `{"foo": "bar"}`,
+				patch:    `[{"op": "unknown", "path": "/foo"}]`,
+			},
+			wantErr:        ErrInvalidOperation,
+			wantStatusCode: http.StatusBadRequest,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			gotDocument, gotErr :=
+				ApplyJSONPatch([]byte(data.args.document), []byte(data.args.patch))
+
+			if data.wantErr == nil {
+				assert.JSONEq(test, data.wantDocument, string(gotDocument))
+				assert.NoError(test, gotErr)
+				return
+			}
+
+			var patchErr PatchError
+			assert.Nil(test, gotDocument)
+			assert.True(test, errors.As(gotErr, &patchErr))
+			assert.True(test, errors.Is(gotErr, data.wantErr))
+			gotStatusCode := ResolveHTTPError(gotErr).StatusCode()
+			assert.Equal(test, data.wantStatusCode, gotStatusCode)
+		})
+	}
+}
+
+func TestPatchError_Error(test *testing.T) {
+	err := PatchError{
+		OperationIndex: 1,
+		Operation:      "test",
+		Path:           "/baz",
+		Err:            ErrTestFailed,
+	}
+	got := err.Error()
+
+	assert.Equal(test, `operation #1 (test "/baz"): the test operation failed`, got)
+}
+
+func TestReadMergePatchRequest(test *testing.T) {
+	type testOwner struct {
+		Name string `json:"name"`
+	}
+	type testResource struct {
+		Name  string     `json:"name"`
+		Tags  []string   `json:"tags,omitempty"`
+		Owner *testOwner `json:"owner,omitempty"`
+	}
+
+	for _, data := range []struct {
+		name           string
+		contentType    string
+		patch          string
+		options        []ReadJSONOption
+		wantTarget     testResource
+		wantErr        string
+		wantStatusCode int
+	}{
+		{
+			name:        "success",
+			contentType: MergePatchContentType,
+			patch:       `{"name": "new", "tags": null}`,
+			options: []ReadJSONOption{
+				WithMaxSize(30),
+				WithDisallowedUnknownFields(),
+			},
+			wantTarget: testResource{Name: "new", Owner: &testOwner{Name: "owner"}},
+			wantErr:    "",
+		},
+		{
+			name:        "success with the removed pointer field",
+			contentType: MergePatchContentType,
+			patch:       `{"owner": null}`,
+			options:     nil,
+			wantTarget:  testResource{Name: "old", Tags: []string{"one"}},
+			wantErr:     "",
+		},
+		{
+			name:        "error with the content type",
+			contentType: "application/json",
+			patch:       `{"name": "new"}`,
+			options:     nil,
+			wantTarget: testResource{
+				Name:  "old",
+				Tags:  []string{"one"},
+				Owner: &testOwner{Name: "owner"},
+			},
+			wantErr: "unable to read the request: the content type " +
+				`"application/json" isn't "application/merge-patch+json": ` +
+				"the media type is unsupported",
+			wantStatusCode: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:        "error with the validation of the result",
+			contentType: MergePatchContentType,
+			patch:       `{"name": ""}`,
+			options: []ReadJSONOption{
+				WithValidator(func(data interface{}) error {
+					var validationErr ValidationError
+					if data.(*testResource).Name == "" {
+						validationErr.Add("name", "is required")
+					}
+
+					return validationErr.ErrorOrNil()
+				}),
+			},
+			wantTarget: testResource{
+				Name:  "old",
+				Tags:  []string{"one"},
+				Owner: &testOwner{Name: "owner"},
+			},
+			wantErr: "unable to read the result: unable to validate the data: " +
+				"the data is invalid: name: is required",
+			wantStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:        "error with the unknown fields in the result",
+			contentType: MergePatchContentType,
+			patch:       `{"unknown": 23}`,
+			options:     []ReadJSONOption{WithDisallowedUnknownFields()},
+			wantTarget: testResource{
+				Name:  "old",
+				Tags:  []string{"one"},
+				Owner: &testOwner{Name: "owner"},
+			},
+			wantErr: "unable to read the result: unable to unmarshal the data: " +
+				`json: unknown field "unknown"`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			request := httptest.NewRequest(
+				http.MethodPatch,
+				"http://example.com/",
+				strings.NewReader(data.patch),
+			)
+			request.Header.Set("Content-Type", data.contentType)
+
+			target := testResource{
+				Name:  "old",
+				Tags:  []string{"one"},
+				Owner: &testOwner{Name: "owner"},
+			}
+			err := ReadMergePatchRequest(
+				httptest.NewRecorder(),
+				request,
+				&target,
+				data.options...,
+			)
+
+			assert.Equal(test, data.wantTarget, target)
+			if data.wantErr == "" {
+				assert.NoError(test, err)
+				return
+			}
+
+			assert.EqualError(test, err, data.wantErr)
+			gotStatusCode := ResolveHTTPError(err).StatusCode()
+			assert.Equal(test, data.wantStatusCode, gotStatusCode)
+		})
+	}
+}
+
+func TestReadJSONPatchRequest(test *testing.T) {
+	type testResource struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	}
+
+	for _, data := range []struct {
+		name           string
+		patch          string
+		wantTarget     testResource
+		wantErr        assert.ErrorAssertionFunc
+		wantStatusCode int
+	}{
+		{
+			name: "success",
+			patch: `[
+				{"op": "test", "path": "/name", "value": "old"},
+				{"op": "add", "path": "/tags/-", "value": "two"}
+			]`,
+			wantTarget: testResource{Name: "old", Tags: []string{"one", "two"}},
+			wantErr:    assert.NoError,
+		},
+		{
+			name:           "error with the failed test",
+			patch:          `[{"op": "test", "path": "/name", "value": "new"}]`,
+			wantTarget:     testResource{Name: "old", Tags: []string{"one"}},
+			wantErr:        assert.Error,
+			wantStatusCode: http.StatusConflict,
+		},
+		{
+			name:           "error with the malformed patch",
+			patch:          `{"op": "test"}`,
+			wantTarget:     testResource{Name: "old", Tags: []string{"one"}},
+			wantErr:        assert.Error,
+			wantStatusCode: http.StatusBadRequest,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			request := httptest.NewRequest(
+				http.MethodPatch,
+				"http://example.com/",
+				strings.NewReader(data.patch),
+			)
+			request.Header.Set("Content-Type", JSONPatchContentType)
+
+			target := testResource{Name: "old", Tags: []string{"one"}}
+			err := ReadJSONPatchRequest(httptest.NewRecorder(), request, &target)
+
+			assert.Equal(test, data.wantTarget, target)
+			data.wantErr(test, err)
+			if err != nil {
+				gotStatusCode := ResolveHTTPError(err).StatusCode()
+				assert.Equal(test, data.wantStatusCode, gotStatusCode)
+			}
+		})
+	}
+}
+
+func TestReadMergePatchRequest_withHiddenFields(test *testing.T) {
+	type testAuthor struct {
+		Name  string `json:"name"`
+		Email string `json:"-"`
+	}
+	type testResource struct {
+		Name         string            `json:"name"`
+		Labels       map[string]string `json:"labels,omitempty"`
+		Author       *testAuthor       `json:"author,omitempty"`
+		PasswordHash string            `json:"-"`
+		secret       string
+	}
+
+	request := httptest.NewRequest(
+		http.MethodPatch,
+		"http://example.com/",
+		strings.NewReader(`{
+			"name": "new",
+			"labels": {"one": null},
+			"author": {"name": "new author"}
+		}`),
+	)
+	request.Header.Set("Content-Type", MergePatchContentType)
+
+	author := &testAuthor{Name: "author", Email: "author@example.com"}
+	target := testResource{
+		Name:         "old",
+		Labels:       map[string]string{"one": "1", "two": "2"},
+		Author:       author,
+		PasswordHash: "hash",
+		secret:       "secret",
+	}
+	err := ReadMergePatchRequest(httptest.NewRecorder(), request, &target)
+
+	wantTarget := testResource{
+		Name:         "new",
+		Labels:       map[string]string{"two": "2"},
+		Author:       &testAuthor{Name: "new author", Email: "author@example.com"},
+		PasswordHash: "hash",
+		secret:       "secret",
+	}
+	// the original nested structure should stay unchanged
+	wantAuthor := &testAuthor{Name: "author", Email: "author@example.com"}
+	assert.NoError(test, err)
+	assert.Equal(test, wantTarget, target)
+	assert.Equal(test, wantAuthor, author)
+}
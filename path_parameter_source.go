@@ -0,0 +1,92 @@
+package httputils
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// PathParameterSource ...
+//
+// It extracts the path parameter with the specified name from the request.
+// It allows to use the parsing of path parameters with different routers.
+//
+type PathParameterSource interface {
+	PathParameter(request *http.Request, name string) (value string, ok bool)
+}
+
+// PathParameterSourceFunc ...
+//
+// It's an adapter to use a function as the PathParameterSource interface,
+// e.g. for the github.com/go-chi/chi package:
+//
+//	PathParameterSourceFunc(
+//		func(request *http.Request, name string) (string, bool) {
+//			value := chi.URLParam(request, name)
+//			return value, value != ""
+//		},
+//	)
+//
+type PathParameterSourceFunc func(
+	request *http.Request,
+	name string,
+) (value string, ok bool)
+
+// MapPathParameterSource ...
+//
+// It's the PathParameterSource interface that takes path parameters
+// from the map regardless of the request. It's useful for custom routers
+// and for testing purposes.
+//
+type MapPathParameterSource map[string]string
+
+// MuxPathParameterSource ...
+//
+// It's the PathParameterSource interface for the github.com/gorilla/mux
+// package (see the mux.Vars() function).
+//
+var MuxPathParameterSource PathParameterSource = PathParameterSourceFunc(
+	func(request *http.Request, name string) (string, bool) {
+		value, ok := mux.Vars(request)[name]
+		return value, ok
+	},
+)
+
+// ServeMuxPathParameterSource ...
+//
+// It's the PathParameterSource interface for the http.ServeMux router
+// with patterns (see the http.Request.PathValue() method).
+//
+// Since the http.Request.PathValue() method doesn't distinguish a missing
+// parameter from an empty one, an empty value is considered missing.
+//
+var ServeMuxPathParameterSource PathParameterSource = PathParameterSourceFunc(
+	func(request *http.Request, name string) (string, bool) {
+		value := request.PathValue(name)
+		return value, value != ""
+	},
+)
+
+// PathParameter ...
+//
+// It calls the function. It implements the PathParameterSource interface.
+//
+func (source PathParameterSourceFunc) PathParameter(
+	request *http.Request,
+	name string,
+) (value string, ok bool) {
+	return source(request, name)
+}
+
+// PathParameter ...
+//
+// It returns the value from the map. It implements the PathParameterSource
+// interface.
+//
+func (source MapPathParameterSource) PathParameter(
+	request *http.Request,
+	name string,
+) (value string, ok bool) {
+	value, ok = source[name]
+	return value, ok
+}
@@ -0,0 +1,128 @@
+package httputils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathParameterSource(test *testing.T) {
+	type args struct {
+		request *http.Request
+		name    string
+	}
+
+	for _, data := range []struct {
+		name      string
+		source    PathParameterSource
+		args      args
+		wantValue string
+		wantOk    bool
+	}{
+		{
+			name:   "mux/success",
+			source: MuxPathParameterSource,
+			args: args{
+				request: mux.SetURLVars(
+					httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
+					map[string]string{"test": "23"},
+				),
+				name: "test",
+			},
+			wantValue: "23",
+			wantOk:    true,
+		},
+		{
+			name:   "mux/missing",
+			source: MuxPathParameterSource,
+			args: args{
+				request: httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
+				name:    "test",
+			},
+			wantValue: "",
+			wantOk:    false,
+		},
+		{
+			name:   "serve mux/success",
+			source: ServeMuxPathParameterSource,
+			args: args{
+				request: func() *http.Request {
+					request :=
+						httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+					request.SetPathValue("test", "23")
+
+					return request
+				}(),
+				name: "test",
+			},
+			wantValue: "23",
+			wantOk:    true,
+		},
+		{
+			name:   "serve mux/missing",
+			source: ServeMuxPathParameterSource,
+			args: args{
+				request: httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
+				name:    "test",
+			},
+			wantValue: "",
+			wantOk:    false,
+		},
+		{
+			name:   "map/success",
+			source: MapPathParameterSource{"test": "23"},
+			args: args{
+				request: httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
+				name:    "test",
+			},
+			wantValue: "23",
+			wantOk:    true,
+		},
+		{
+			name:   "map/missing",
+			source: MapPathParameterSource{"other": "23"},
+			args: args{
+				request: httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
+				name:    "test",
+			},
+			wantValue: "",
+			wantOk:    false,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			gotValue, gotOk :=
+				data.source.PathParameter(data.args.request, data.args.name)
+
+			assert.Equal(test, data.wantValue, gotValue)
+			assert.Equal(test, data.wantOk, gotOk)
+		})
+	}
+}
+
+func TestParsePathParameter_withServeMux(test *testing.T) {
+	var gotID int
+	var gotErr error
+	router := http.NewServeMux()
+	router.HandleFunc(
+		"/users/{id}",
+		func(writer http.ResponseWriter, request *http.Request) {
+			gotErr = ParsePathParameter(
+				request,
+				"id",
+				&gotID,
+				WithPathParameterSource(ServeMuxPathParameterSource),
+			)
+		},
+	)
+
+	router.ServeHTTP(
+		httptest.NewRecorder(),
+		httptest.NewRequest(http.MethodGet, "http://example.com/users/23", nil),
+	)
+
+	assert.Equal(test, 23, gotID)
+	assert.NoError(test, gotErr)
+}
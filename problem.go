@@ -0,0 +1,225 @@
+package httputils
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-log/log"
+	"github.com/pkg/errors"
+)
+
+// ProblemContentType ...
+//
+// It's the media type of problem details defined in RFC 7807.
+//
+const ProblemContentType = "application/problem+json"
+
+const defaultProblemType = "about:blank"
+
+var problemMembers = []string{"type", "title", "status", "detail", "instance"}
+
+// Problem ...
+//
+// It represents problem details for HTTP APIs defined in RFC 7807.
+// See: https://datatracker.ietf.org/doc/html/rfc7807
+//
+// Extension members are marshalled at the same level as the standard ones.
+// On a name conflict, the standard members take precedence.
+//
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]interface{}
+}
+
+// ProblemProvider ...
+//
+// It's implemented by errors that can describe themselves as problem details.
+//
+type ProblemProvider interface {
+	Problem() Problem
+}
+
+// StatusCodeProvider ...
+//
+// It's implemented by errors that know the corresponding HTTP status code.
+//
+type StatusCodeProvider interface {
+	StatusCode() int
+}
+
+// NewProblem ...
+//
+// It builds problem details from the error.
+//
+// If the error (or any error in its chain) implements the ProblemProvider
+// interface, its problem details are used. Missing members are filled
+// according to the ResolveHTTPError() function (with the provided status code
+// as the default one): the status code, the status text as the title,
+// the public message as the detail and the code as the "code" extension member.
+// The "about:blank" type is used by default.
+//
+func NewProblem(err error, statusCode int) Problem {
+	var problem Problem
+	var problemProvider ProblemProvider
+	if errors.As(err, &problemProvider) {
+		problem = problemProvider.Problem()
+	}
+
+	httpErr := resolveHTTPError(err, statusCode)
+	if problem.Type == "" {
+		problem.Type = defaultProblemType
+	}
+	if problem.Status == 0 {
+		problem.Status = httpErr.StatusCode()
+	}
+	if problem.Title == "" {
+		problem.Title = http.StatusText(problem.Status)
+	}
+	if problem.Detail == "" {
+		problem.Detail = httpErr.PublicMessage()
+	}
+	if _, ok := problem.Extensions["code"]; !ok && httpErr.Code() != "" {
+		extensions := make(map[string]interface{}, len(problem.Extensions)+1)
+		for name, value := range problem.Extensions {
+			extensions[name] = value
+		}
+		extensions["code"] = httpErr.Code()
+
+		problem.Extensions = extensions
+	}
+
+	return problem
+}
+
+// MarshalJSON ...
+//
+// It implements the json.Marshaler interface. Empty standard members
+// are omitted.
+//
+func (problem Problem) MarshalJSON() ([]byte, error) {
+	members := make(map[string]interface{}, len(problem.Extensions)+5)
+	for name, value := range problem.Extensions {
+		members[name] = value
+	}
+	for _, name := range problemMembers {
+		delete(members, name)
+	}
+
+	if problem.Type != "" {
+		members["type"] = problem.Type
+	}
+	if problem.Title != "" {
+		members["title"] = problem.Title
+	}
+	if problem.Status != 0 {
+		members["status"] = problem.Status
+	}
+	if problem.Detail != "" {
+		members["detail"] = problem.Detail
+	}
+	if problem.Instance != "" {
+		members["instance"] = problem.Instance
+	}
+
+	return json.Marshal(members)
+}
+
+// UnmarshalJSON ...
+//
+// It implements the json.Unmarshaler interface. All non-standard members
+// are put to extensions.
+//
+func (problem *Problem) UnmarshalJSON(bytes []byte) error {
+	var standardMembers struct {
+		Type     string `json:"type"`
+		Title    string `json:"title"`
+		Status   int    `json:"status"`
+		Detail   string `json:"detail"`
+		Instance string `json:"instance"`
+	}
+	if err := json.Unmarshal(bytes, &standardMembers); err != nil {
+		return errors.Wrap(err, "unable to unmarshal the standard members")
+	}
+
+	var extensions map[string]interface{}
+	if err := json.Unmarshal(bytes, &extensions); err != nil {
+		return errors.Wrap(err, "unable to unmarshal the extension members")
+	}
+	for _, name := range problemMembers {
+		delete(extensions, name)
+	}
+	if len(extensions) == 0 {
+		extensions = nil
+	}
+
+	*problem = Problem{
+		Type:       standardMembers.Type,
+		Title:      standardMembers.Title,
+		Status:     standardMembers.Status,
+		Detail:     standardMembers.Detail,
+		Instance:   standardMembers.Instance,
+		Extensions: extensions,
+	}
+
+	return nil
+}
+
+// WriteProblem ...
+//
+// It's an analog of the LoggingError() function that writes the error
+// as problem details (see the NewProblem() function) with the corresponding
+// content type. The status code of the response is taken from the problem
+// details.
+//
+// An error of the writing is logged via the provided log.Logger interface too.
+// If the problem details can't be written at all (e.g. an extension member
+// can't be marshalled), the plain error with
+// the http.StatusInternalServerError status code is written instead,
+// so that the error doesn't turn into a successful response.
+//
+func WriteProblem(
+	logger log.Logger,
+	writer http.ResponseWriter,
+	err error,
+	statusCode int,
+) {
+	logger.Log(err.Error())
+
+	problem := NewProblem(err, statusCode)
+	catchingWriter := NewCatchingResponseWriter(writer)
+	err = writeJSON(catchingWriter, problem.Status, ProblemContentType, problem)
+	if err != nil {
+		logger.Logf("unable to write the problem details: %v", err)
+		writeFallbackError(catchingWriter)
+	}
+}
+
+// WriteProblemError ...
+//
+// It's a shortcut for the WriteProblem() function
+// with the http.StatusInternalServerError status code as the default one.
+// It has the ErrorWriter signature.
+//
+func WriteProblemError(
+	logger log.Logger,
+	writer http.ResponseWriter,
+	err error,
+) {
+	WriteProblem(logger, writer, err, http.StatusInternalServerError)
+}
+
+// it writes the plain error with the http.StatusInternalServerError status
+// code if nothing is written yet, so that a failed rendering of an error
+// doesn't turn into an empty successful response
+func writeFallbackError(writer *CatchingResponseWriter) {
+	if writer.HeaderWritten() {
+		return
+	}
+
+	statusCode := http.StatusInternalServerError
+	http.Error(writer, http.StatusText(statusCode), statusCode)
+}
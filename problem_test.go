@@ -0,0 +1,365 @@
+package httputils
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/iotest"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type testStatusCodeError struct {
+	statusCode int
+}
+
+func (err testStatusCodeError) Error() string {
+	return "test status code error"
+}
+
+func (err testStatusCodeError) StatusCode() int {
+	return err.statusCode
+}
+
+type testProblemError struct {
+	problem Problem
+}
+
+func (err testProblemError) Error() string {
+	return "test problem error"
+}
+
+func (err testProblemError) Problem() Problem {
+	return err.problem
+}
+
+func TestNewProblem(test *testing.T) {
+	type args struct {
+		err        error
+		statusCode int
+	}
+
+	for _, data := range []struct {
+		name string
+		args args
+		want Problem
+	}{
+		{
+			name: "with a plain error",
+			args: args{
+				err:        iotest.ErrTimeout,
+				statusCode: http.StatusServiceUnavailable,
+			},
+			want: Problem{
+				Type:   "about:blank",
+				Title:  "Service Unavailable",
+				Status: http.StatusServiceUnavailable,
+				Detail: "Service Unavailable",
+			},
+		},
+		{
+			name: "with the HTTP error",
+			args: args{
+				err: errors.Wrap(
+					Conflict(iotest.ErrTimeout).WithCode("already_exists"),
+					"dummy",
+				),
+				statusCode: http.StatusInternalServerError,
+			},
+			want: Problem{
+				Type:       "about:blank",
+				Title:      "Conflict",
+				Status:     http.StatusConflict,
+				Detail:     "dummy: timeout",
+				Extensions: map[string]interface{}{"code": "already_exists"},
+			},
+		},
+		{
+			name: "with the status code provider",
+			args: args{
+				err: errors.Wrap(
+					testStatusCodeError{statusCode: http.StatusNotFound},
+					"dummy",
+				),
+				statusCode: http.StatusInternalServerError,
+			},
+			want: Problem{
+				Type:   "about:blank",
+				Title:  "Not Found",
+				Status: http.StatusNotFound,
+				Detail: "dummy: test status code error",
+			},
+		},
+		{
+			name: "with the problem provider/full",
+			args: args{
+				err: errors.Wrap(
+					testProblemError{
+						problem: Problem{
+							Type:       "https://example.com/probs/out-of-credit",
+							Title:      "You do not have enough credit.",
+							Status:     http.StatusForbidden,
+							Detail:     "Your current balance is 30, but that costs 50.",
+							Instance:   "/account/12345/msgs/abc",
+							Extensions: map[string]interface{}{"balance": 30},
+						},
+					},
+					"dummy",
+				),
+				statusCode: http.StatusInternalServerError,
+			},
+			want: Problem{
+				Type:       "https://example.com/probs/out-of-credit",
+				Title:      "You do not have enough credit.",
+				Status:     http.StatusForbidden,
+				Detail:     "Your current balance is 30, but that costs 50.",
+				Instance:   "/account/12345/msgs/abc",
+				Extensions: map[string]interface{}{"balance": 30},
+			},
+		},
+		{
+			name: "with the problem provider/partial",
+			args: args{
+				err: testProblemError{
+					problem: Problem{Instance: "/account/12345/msgs/abc"},
+				},
+				statusCode: http.StatusBadRequest,
+			},
+			want: Problem{
+				Type:     "about:blank",
+				Title:    "Bad Request",
+				Status:   http.StatusBadRequest,
+				Detail:   "test problem error",
+				Instance: "/account/12345/msgs/abc",
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := NewProblem(data.args.err, data.args.statusCode)
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestProblem_MarshalJSON(test *testing.T) {
+	for _, data := range []struct {
+		name    string
+		problem Problem
+		want    string
+	}{
+		{
+			name:    "empty",
+			problem: Problem{},
+			want:    "{}",
+		},
+		{
+			name: "full",
+			problem: Problem{
+				Type:     "https://example.com/probs/out-of-credit",
+				Title:    "You do not have enough credit.",
+				Status:   http.StatusForbidden,
+				Detail:   "Your current balance is 30, but that costs 50.",
+				Instance: "/account/12345/msgs/abc",
+				Extensions: map[string]interface{}{
+					"balance": 30,
+					"title":   "conflicting",
+				},
+			},
+			want: `{
+				"type": "https://example.com/probs/out-of-credit",
+				"title": "You do not have enough credit.",
+				"status": 403,
+				"detail": "Your current balance is 30, but that costs 50.",
+				"instance": "/account/12345/msgs/abc",
+				"balance": 30
+			}`,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, gotErr := data.problem.MarshalJSON()
+
+			assert.JSONEq(test, data.want, string(got))
+			assert.NoError(test, gotErr)
+		})
+	}
+}
+
+func TestProblem_UnmarshalJSON(test *testing.T) {
+	for _, data := range []struct {
+		name        string
+		bytes       string
+		wantProblem Problem
+		wantErr     assert.ErrorAssertionFunc
+	}{
+		{
+			name:        "success/empty",
+			bytes:       "{}",
+			wantProblem: Problem{},
+			wantErr:     assert.NoError,
+		},
+		{
+			name: "success/full",
+			bytes: `{
+				"type": "https://example.com/probs/out-of-credit",
+				"title": "You do not have enough credit.",
+				"status": 403,
+				"detail": "Your current balance is 30, but that costs 50.",
+				"instance": "/account/12345/msgs/abc",
+				"balance": 30
+			}`,
+			wantProblem: Problem{
+				Type:       "https://example.com/probs/out-of-credit",
+				Title:      "You do not have enough credit.",
+				Status:     http.StatusForbidden,
+				Detail:     "Your current balance is 30, but that costs 50.",
+				Instance:   "/account/12345/msgs/abc",
+				Extensions: map[string]interface{}{"balance": float64(30)},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:        "error",
+			bytes:       `{"status": "incorrect"}`,
+			wantProblem: Problem{},
+			wantErr:     assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			var gotProblem Problem
+			gotErr := gotProblem.UnmarshalJSON([]byte(data.bytes))
+
+			assert.Equal(test, data.wantProblem, gotProblem)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestWriteProblem(test *testing.T) {
+	type args struct {
+		err        error
+		statusCode int
+	}
+
+	for _, data := range []struct {
+		name           string
+		args           args
+		writeErr       error
+		wantLogs       [][]interface{}
+		wantStatusCode int
+		wantBody       string
+	}{
+		{
+			name: "success",
+			args: args{
+				err:        testStatusCodeError{statusCode: http.StatusNotFound},
+				statusCode: http.StatusInternalServerError,
+			},
+			writeErr: nil,
+			wantLogs: [][]interface{}{
+				{"test status code error"},
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantBody: `{"detail":"test status code error","status":404,` +
+				`"title":"Not Found","type":"about:blank"}`,
+		},
+		{
+			name: "error",
+			args: args{
+				err:        iotest.ErrTimeout,
+				statusCode: http.StatusServiceUnavailable,
+			},
+			writeErr: iotest.ErrTimeout,
+			wantLogs: [][]interface{}{
+				{"timeout"},
+				{
+					"unable to write the problem details: %v",
+					mock.MatchedBy(func(err error) bool {
+						return errors.Cause(err) == iotest.ErrTimeout
+					}),
+				},
+			},
+			wantStatusCode: http.StatusServiceUnavailable,
+			wantBody: `{"detail":"Service Unavailable","status":503,` +
+				`"title":"Service Unavailable","type":"about:blank"}`,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			logger := new(MockLogger)
+			for _, arguments := range data.wantLogs {
+				if len(arguments) == 1 {
+					logger.On("Log", arguments...).Return()
+				} else {
+					logger.On("Logf", arguments...).Return()
+				}
+			}
+
+			writer := new(MockResponseWriter)
+			writer.On("Header").Return(http.Header{})
+			writer.On("WriteHeader", data.wantStatusCode).Return()
+			writer.
+				On("Write", []byte(data.wantBody)).
+				Return(len(data.wantBody), data.writeErr)
+
+			WriteProblem(logger, writer, data.args.err, data.args.statusCode)
+
+			wantHeader := http.Header{"Content-Type": {ProblemContentType}}
+			mock.AssertExpectationsForObjects(test, logger, writer)
+			assert.Equal(test, wantHeader, writer.Header())
+		})
+	}
+}
+
+func TestWriteProblem_withMarshallingError(test *testing.T) {
+	err := testProblemError{
+		problem: Problem{Extensions: map[string]interface{}{"value": math.NaN()}},
+	}
+
+	logger := new(MockLogger)
+	logger.On("Log", "test problem error").Return()
+	logger.
+		On(
+			"Logf",
+			"unable to write the problem details: %v",
+			mock.MatchedBy(func(err error) bool {
+				var unsupportedValueErr *json.UnsupportedValueError
+				return errors.As(err, &unsupportedValueErr)
+			}),
+		).
+		Return()
+
+	recorder := httptest.NewRecorder()
+	WriteProblem(logger, recorder, err, http.StatusBadRequest)
+
+	wantHeader := http.Header{
+		"Content-Type":           {"text/plain; charset=utf-8"},
+		"X-Content-Type-Options": {"nosniff"},
+	}
+	mock.AssertExpectationsForObjects(test, logger)
+	assert.Equal(test, http.StatusInternalServerError, recorder.Code)
+	assert.Equal(test, wantHeader, recorder.Header())
+	assert.Equal(test, "Internal Server Error\n", recorder.Body.String())
+}
+
+func TestWriteProblemError(test *testing.T) {
+	const body = `{"detail":"Internal Server Error","status":500,` +
+		`"title":"Internal Server Error","type":"about:blank"}`
+
+	logger := new(MockLogger)
+	logger.On("Log", "timeout").Return()
+
+	writer := new(MockResponseWriter)
+	writer.On("Header").Return(http.Header{})
+	writer.On("WriteHeader", http.StatusInternalServerError).Return()
+	writer.On("Write", []byte(body)).Return(len(body), nil)
+
+	WriteProblemError(logger, writer, iotest.ErrTimeout)
+
+	wantHeader := http.Header{"Content-Type": {ProblemContentType}}
+	mock.AssertExpectationsForObjects(test, logger, writer)
+	assert.Equal(test, wantHeader, writer.Header())
+}
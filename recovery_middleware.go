@@ -0,0 +1,102 @@
+package httputils
+
+import (
+	"net/http"
+	"runtime/debug"
+
+	"github.com/go-log/log"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+// PanicReporter ...
+//
+// It reports a panic recovered by the RecoveryMiddleware() middleware
+// (e.g. to an error tracking service).
+//
+type PanicReporter func(request *http.Request, value interface{}, stack []byte)
+
+// RecoveryOption ...
+//
+// It sets an optional parameter of the RecoveryMiddleware() middleware.
+//
+type RecoveryOption func(options *recoveryOptions)
+
+type recoveryOptions struct {
+	reporter PanicReporter
+}
+
+// WithPanicReporter ...
+//
+// It sets the reporter that is called for each recovered panic
+// in addition to the logging.
+//
+func WithPanicReporter(reporter PanicReporter) RecoveryOption {
+	return func(options *recoveryOptions) {
+		options.reporter = reporter
+	}
+}
+
+// RecoveryMiddleware ...
+//
+// It's a middleware that recovers a panic in the next handler and logs
+// its value and stack via the provided log.Logger interface.
+//
+// If the header wasn't sent yet, the middleware responds with
+// the http.StatusInternalServerError status code via the LoggingError()
+// function. The panic value isn't sent to the client. Otherwise, after
+// the logging and the reporting, the middleware panics with
+// the http.ErrAbortHandler value, so that the http.Server structure aborts
+// the connection, and the client doesn't take the truncated response
+// as a complete one.
+//
+// The http.ErrAbortHandler panic is not handled and is re-panicked,
+// so that the http.Server structure can abort the response as usual.
+//
+func RecoveryMiddleware(
+	logger log.Logger,
+	options ...RecoveryOption,
+) mux.MiddlewareFunc {
+	var recoveryOptions recoveryOptions
+	for _, option := range options {
+		option(&recoveryOptions)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(
+			writer http.ResponseWriter,
+			request *http.Request,
+		) {
+			catchingWriter := NewCatchingResponseWriter(writer)
+			defer func() {
+				value := recover()
+				if value == nil {
+					return
+				}
+				if err, ok := value.(error); ok && err == http.ErrAbortHandler {
+					panic(value)
+				}
+
+				stack := debug.Stack()
+				logger.Logf(
+					"panic while handling the HTTP request: %v\n%s",
+					value,
+					stack,
+				)
+				if recoveryOptions.reporter != nil {
+					recoveryOptions.reporter(request, value, stack)
+				}
+
+				if catchingWriter.HeaderWritten() {
+					// the response can't be completed correctly, so abort it
+					panic(http.ErrAbortHandler)
+				}
+
+				err := errors.New("the HTTP handler panicked")
+				LoggingError(logger, writer, err, http.StatusInternalServerError)
+			}()
+
+			next.ServeHTTP(catchingWriter.WithOptionalInterfaces(), request)
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package httputils
+
+import (
+	"io"
+	"io/ioutil"
+	stdlog "log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/go-log/log"
+	"github.com/go-log/log/print"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func ExampleRecoveryMiddleware() {
+	// use the standard logger for error handling
+	logger := stdlog.New(os.Stderr, "", stdlog.LstdFlags)
+	recoveryMiddleware := RecoveryMiddleware(
+		// wrap the standard logger via the github.com/go-log/log package
+		print.New(logger),
+		WithPanicReporter(func(request *http.Request, value interface{}, _ []byte) {
+			// send the panic to an error tracking service
+		}),
+	)
+
+	var handler http.Handler // nolint: staticcheck
+	handler = http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		// the panic will be handled by the recovery middleware
+		panic("test")
+	})
+	handler = recoveryMiddleware(handler)
+
+	http.Handle("/", handler)
+	logger.Fatal(http.ListenAndServe(":8080", nil))
+}
+
+func TestRecoveryMiddleware(test *testing.T) {
+	type args struct {
+		logger  log.Logger
+		options []RecoveryOption
+	}
+	type middlewareArgs struct {
+		next http.Handler
+	}
+
+	for _, data := range []struct {
+		name           string
+		args           args
+		middlewareArgs middlewareArgs
+		wantStatusCode int
+		wantBody       string
+		wantPanic      interface{}
+		wantReports    []interface{}
+	}{
+		{
+			name: "without a panic",
+			args: args{
+				logger:  new(MockLogger),
+				options: nil,
+			},
+			middlewareArgs: middlewareArgs{
+				next: http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+					writer.Write([]byte("test")) // nolint: errcheck
+				}),
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       "test",
+			wantPanic:      nil,
+			wantReports:    nil,
+		},
+		{
+			name: "with a panic/before writing",
+			args: args{
+				logger: func() log.Logger {
+					logger := new(MockLogger)
+					logger.
+						On(
+							"Logf",
+							"panic while handling the HTTP request: %v\n%s",
+							"test",
+							mock.AnythingOfType("[]uint8"),
+						).
+						Return()
+					logger.On("Log", "the HTTP handler panicked").Return()
+
+					return logger
+				}(),
+				options: nil,
+			},
+			middlewareArgs: middlewareArgs{
+				next: http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+					panic("test")
+				}),
+			},
+			wantStatusCode: http.StatusInternalServerError,
+			wantBody:       "the HTTP handler panicked\n",
+			wantPanic:      nil,
+			wantReports:    nil,
+		},
+		{
+			name: "with a panic/after writing",
+			args: args{
+				logger: func() log.Logger {
+					logger := new(MockLogger)
+					logger.
+						On(
+							"Logf",
+							"panic while handling the HTTP request: %v\n%s",
+							"test",
+							mock.AnythingOfType("[]uint8"),
+						).
+						Return()
+
+					return logger
+				}(),
+				options: nil,
+			},
+			middlewareArgs: middlewareArgs{
+				next: http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+					writer.Write([]byte("test")) // nolint: errcheck
+					panic("test")
+				}),
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       "test",
+			wantPanic:      http.ErrAbortHandler,
+			wantReports:    nil,
+		},
+		{
+			name: "with a panic/after writing with the reporter",
+			args: args{
+				logger: func() log.Logger {
+					logger := new(MockLogger)
+					logger.
+						On(
+							"Logf",
+							"panic while handling the HTTP request: %v\n%s",
+							"test",
+							mock.AnythingOfType("[]uint8"),
+						).
+						Return()
+
+					return logger
+				}(),
+				options: nil, // it will be set in the test
+			},
+			middlewareArgs: middlewareArgs{
+				next: http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+					writer.Write([]byte("test")) // nolint: errcheck
+					panic("test")
+				}),
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       "test",
+			wantPanic:      http.ErrAbortHandler,
+			wantReports:    []interface{}{"test"},
+		},
+		{
+			name: "with a panic/with the reporter",
+			args: args{
+				logger: func() log.Logger {
+					logger := new(MockLogger)
+					logger.
+						On(
+							"Logf",
+							"panic while handling the HTTP request: %v\n%s",
+							"test",
+							mock.AnythingOfType("[]uint8"),
+						).
+						Return()
+					logger.On("Log", "the HTTP handler panicked").Return()
+
+					return logger
+				}(),
+				options: nil, // it will be set in the test
+			},
+			middlewareArgs: middlewareArgs{
+				next: http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+					panic("test")
+				}),
+			},
+			wantStatusCode: http.StatusInternalServerError,
+			wantBody:       "the HTTP handler panicked\n",
+			wantPanic:      nil,
+			wantReports:    []interface{}{"test"},
+		},
+		{
+			name: "with the http.ErrAbortHandler panic",
+			args: args{
+				logger:  new(MockLogger),
+				options: nil,
+			},
+			middlewareArgs: middlewareArgs{
+				next: http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+					panic(http.ErrAbortHandler)
+				}),
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       "",
+			wantPanic:      http.ErrAbortHandler,
+			wantReports:    nil,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			var gotReports []interface{}
+			options := data.args.options
+			if data.wantReports != nil {
+				options = append(options, WithPanicReporter(func(
+					request *http.Request,
+					value interface{},
+					stack []byte,
+				) {
+					assert.NotNil(test, request)
+					assert.NotEmpty(test, stack)
+
+					gotReports = append(gotReports, value)
+				}))
+			}
+
+			writer := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+			middleware := RecoveryMiddleware(data.args.logger, options...)
+			handler := middleware(data.middlewareArgs.next)
+
+			var gotPanic interface{}
+			func() {
+				defer func() { gotPanic = recover() }()
+				handler.ServeHTTP(writer, request)
+			}()
+
+			mock.AssertExpectationsForObjects(test, data.args.logger)
+			assert.Equal(test, data.wantPanic, gotPanic)
+			assert.Equal(test, data.wantStatusCode, writer.Code)
+			assert.Equal(test, data.wantBody, writer.Body.String())
+			assert.Equal(test, data.wantReports, gotReports)
+		})
+	}
+}
+
+func TestRecoveryMiddleware_withServer(test *testing.T) {
+	logger := new(MockLogger)
+	logger.
+		On(
+			"Logf",
+			"panic while handling the HTTP request: %v\n%s",
+			"test",
+			mock.AnythingOfType("[]uint8"),
+		).
+		Return()
+
+	handler := RecoveryMiddleware(logger)(http.HandlerFunc(func(
+		writer http.ResponseWriter,
+		_ *http.Request,
+	) {
+		writer.Write([]byte("test")) // nolint: errcheck
+		writer.(http.Flusher).Flush()
+
+		panic("test")
+	}))
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	response, err := http.Get(server.URL)
+	if !assert.NoError(test, err) {
+		return
+	}
+	defer response.Body.Close()
+
+	_, gotErr := ioutil.ReadAll(response.Body)
+
+	mock.AssertExpectationsForObjects(test, logger)
+	assert.Equal(test, http.StatusOK, response.StatusCode)
+	assert.Equal(test, io.ErrUnexpectedEOF, gotErr)
+}
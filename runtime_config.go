@@ -0,0 +1,231 @@
+package httputils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-log/log"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+// RuntimeConfigPlaceholder ...
+//
+// It's replaced by the script with the runtime config in the index.html file
+// (see the WithRuntimeConfig() option).
+//
+const RuntimeConfigPlaceholder = "<!-- runtime-config -->"
+
+const indexFileName = "index.html"
+
+var headClosingTagPattern = regexp.MustCompile(`(?i)</head\s*>`)
+
+// StaticAssetOption ...
+//
+// It sets an optional parameter of the StaticAssetHandler() function.
+//
+type StaticAssetOption func(options *staticAssetOptions)
+
+type staticAssetOptions struct {
+	runtimeConfig    interface{}
+	hasRuntimeConfig bool
+	runtimeConfigErr error
+}
+
+type renderedIndex struct {
+	content []byte
+	eTag    string
+}
+
+// WithRuntimeConfig ...
+//
+// It injects the runtime config into the index.html file served
+// from the root of the file system, so the SPA can be built once and deployed
+// to many environments. The config is marshalled to JSON and assigned
+// to the window.__CONFIG__ variable by the script that replaces
+// the RuntimeConfigPlaceholder constant or, if there's no placeholder,
+// is inserted before the closing tag of the head element.
+//
+// The JSON is safe to embed into the script, since the json.Marshal()
+// function escapes the <, > and & characters as well as the line
+// and paragraph separators.
+//
+// The rendered file is cached on the first request, so changes of the file
+// are not reflected until the handler is recreated. The Content-Length
+// and ETag headers correspond to the rendered file, and conditional and range
+// requests are supported (see the http.ServeContent() function).
+//
+// The Last-Modified header isn't set, since the modification time of the file
+// doesn't reflect changes of the config, so a conditional request
+// with the If-Modified-Since header could get a stale response. Therefore,
+// only the ETag header is used for validation.
+//
+func WithRuntimeConfig(config interface{}) StaticAssetOption {
+	return func(options *staticAssetOptions) {
+		options.runtimeConfig = config
+		options.hasRuntimeConfig = true
+		options.runtimeConfigErr = nil
+	}
+}
+
+// WithRuntimeConfigFromEnv ...
+//
+// It's an analog of the WithRuntimeConfig() option that takes the config
+// from environment variables with the specified prefix. The config is a JSON
+// object, where names of the variables without the prefix are keys, and values
+// of the variables are values. The variables are read once on the creation
+// of the handler.
+//
+// The config is public, so the prefix should select only variables intended
+// for the client. Therefore, the empty prefix, which would expose the whole
+// environment (including secrets), is considered a programming error.
+// In this case, the runtime config isn't injected at all, and the error
+// is logged by the StaticAssetHandler() function via its logger.
+//
+func WithRuntimeConfigFromEnv(prefix string) StaticAssetOption {
+	return func(options *staticAssetOptions) {
+		if prefix == "" {
+			options.runtimeConfig = nil
+			options.hasRuntimeConfig = false
+			options.runtimeConfigErr =
+				errors.New("the prefix of the environment variables is empty")
+
+			return
+		}
+
+		config := make(map[string]string)
+		for _, variable := range os.Environ() {
+			name, value, _ := strings.Cut(variable, "=")
+			if strings.HasPrefix(name, prefix) {
+				config[strings.TrimPrefix(name, prefix)] = value
+			}
+		}
+
+		options.runtimeConfig = config
+		options.hasRuntimeConfig = true
+		options.runtimeConfigErr = nil
+	}
+}
+
+func runtimeConfigMiddleware(
+	fileSystem http.FileSystem,
+	logger log.Logger,
+	config interface{},
+) mux.MiddlewareFunc {
+	var cachedIndex *renderedIndex
+	var cachedIndexLock sync.Mutex
+	loadIndex := func() (*renderedIndex, error) {
+		cachedIndexLock.Lock()
+		defer cachedIndexLock.Unlock()
+
+		if cachedIndex != nil {
+			return cachedIndex, nil
+		}
+
+		index, err := renderIndex(fileSystem, config)
+		if err != nil {
+			return nil, err
+		}
+
+		cachedIndex = index
+		return cachedIndex, nil
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(
+			writer http.ResponseWriter,
+			request *http.Request,
+		) {
+			allowedMethods := []string{http.MethodGet, http.MethodHead}
+			if !isMethodAllowed(request.Method, allowedMethods) ||
+				path.Clean("/"+request.URL.Path) != "/" {
+				next.ServeHTTP(writer, request)
+				return
+			}
+
+			index, err := loadIndex()
+			if errors.Is(err, os.ErrNotExist) {
+				next.ServeHTTP(writer, request)
+				return
+			}
+			if err != nil {
+				err = errors.Wrap(err, "unable to render the index.html file")
+				WriteError(logger, writer, err)
+				return
+			}
+
+			writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+			writer.Header().Set("ETag", index.eTag)
+			http.ServeContent(
+				writer,
+				request,
+				indexFileName,
+				time.Time{}, // see the WithRuntimeConfig() option
+				bytes.NewReader(index.content),
+			)
+		})
+	}
+}
+
+func renderIndex(
+	fileSystem http.FileSystem,
+	config interface{},
+) (*renderedIndex, error) {
+	file, err := fileSystem.Open("/" + indexFileName)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to open the file")
+	}
+	defer file.Close() // nolint: errcheck
+
+	content, err := ioutil.ReadAll(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read the file")
+	}
+
+	content, err = injectRuntimeConfig(content, config)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to inject the runtime config")
+	}
+
+	hash := sha256.Sum256(content)
+	index := &renderedIndex{
+		content: content,
+		eTag:    `"` + hex.EncodeToString(hash[:]) + `"`,
+	}
+	return index, nil
+}
+
+func injectRuntimeConfig(content []byte, config interface{}) ([]byte, error) {
+	configBytes, err := json.Marshal(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to marshal the config")
+	}
+
+	script := "<script>window.__CONFIG__ = " + string(configBytes) + ";</script>"
+	placeholder := []byte(RuntimeConfigPlaceholder)
+	if bytes.Contains(content, placeholder) {
+		return bytes.Replace(content, placeholder, []byte(script), 1), nil
+	}
+
+	headClosingTagIndex := headClosingTagPattern.FindIndex(content)
+	if headClosingTagIndex == nil {
+		return nil, errors.New("there's neither the placeholder nor the head element")
+	}
+
+	var injectedContent bytes.Buffer
+	injectedContent.Write(content[:headClosingTagIndex[0]])
+	injectedContent.WriteString(script)
+	injectedContent.Write(content[headClosingTagIndex[0]:])
+
+	return injectedContent.Bytes(), nil
+}
@@ -0,0 +1,313 @@
+package httputils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/go-log/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStaticAssetHandler_withRuntimeConfig(test *testing.T) {
+	modTime := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+	newFileSystem := func(index string) http.FileSystem {
+		return http.FS(fstest.MapFS{
+			"index.html": {Data: []byte(index), ModTime: modTime},
+			"main.js":    {Data: []byte("main();"), ModTime: modTime},
+		})
+	}
+	newRequest := func(
+		method string,
+		url string,
+		header http.Header,
+	) *http.Request {
+		request := httptest.NewRequest(method, url, nil)
+		for key, values := range header {
+			request.Header[key] = values
+		}
+
+		return request
+	}
+
+	const wantIndex = "<html><head>" +
+		`<script>window.__CONFIG__ = {"apiURL":"/api/v1"};</script>` +
+		"</head></html>"
+	const wantETag = `"0481dd44c5145bfaa4616bac04c6d22f` +
+		`616bf7a2cccd4c8fe4c4e560a9ee91e4"`
+	wantHeader := http.Header{
+		"Accept-Ranges":  {"bytes"},
+		"Content-Length": {strconv.Itoa(len(wantIndex))},
+		"Content-Type":   {"text/html; charset=utf-8"},
+		"Etag":           {wantETag},
+	}
+
+	type args struct {
+		fileSystem http.FileSystem
+		logger     log.Logger
+		options    []StaticAssetOption
+		request    *http.Request
+	}
+
+	for _, data := range []struct {
+		name           string
+		args           args
+		wantStatusCode int
+		wantHeader     http.Header
+		wantBody       string
+	}{
+		{
+			name: "success with the placeholder",
+			args: args{
+				fileSystem: newFileSystem(
+					"<html><head>" + RuntimeConfigPlaceholder + "</head></html>",
+				),
+				logger: new(MockLogger),
+				options: []StaticAssetOption{
+					WithRuntimeConfig(map[string]string{"apiURL": "/api/v1"}),
+				},
+				request: newRequest(http.MethodGet, "http://example.com/", nil),
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeader:     wantHeader,
+			wantBody:       wantIndex,
+		},
+		{
+			name: "success with the head element",
+			args: args{
+				fileSystem: newFileSystem("<html><head></HEAD ></html>"),
+				logger:     new(MockLogger),
+				options: []StaticAssetOption{
+					WithRuntimeConfig(struct {
+						Title string `json:"title"`
+					}{Title: "</script><script>alert(1)</script>\u2028"}),
+				},
+				request: newRequest(http.MethodGet, "http://example.com/", nil),
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeader: http.Header{
+				"Accept-Ranges":  {"bytes"},
+				"Content-Length": {"147"},
+				"Content-Type":   {"text/html; charset=utf-8"},
+				"Etag": {
+					`"1c7ea52f28a1969f4c65af310f59f9f5d291393451cad9bdd39f89eaefa6d870"`,
+				},
+			},
+			wantBody: "<html><head>" +
+				"<script>window.__CONFIG__ = {\"title\":\"" +
+				`\u003c/script\u003e\u003cscript\u003ealert(1)` +
+				`\u003c/script\u003e\u2028` +
+				"\"};</script></HEAD ></html>",
+		},
+		{
+			name: "success with the HEAD method",
+			args: args{
+				fileSystem: newFileSystem(
+					"<html><head>" + RuntimeConfigPlaceholder + "</head></html>",
+				),
+				logger: new(MockLogger),
+				options: []StaticAssetOption{
+					WithRuntimeConfig(map[string]string{"apiURL": "/api/v1"}),
+				},
+				request: newRequest(http.MethodHead, "http://example.com/", nil),
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeader:     wantHeader,
+			wantBody:       "",
+		},
+		{
+			name: "success with the route of the SPA",
+			args: args{
+				fileSystem: newFileSystem(
+					"<html><head>" + RuntimeConfigPlaceholder + "</head></html>",
+				),
+				logger: new(MockLogger),
+				options: []StaticAssetOption{
+					WithRuntimeConfig(map[string]string{"apiURL": "/api/v1"}),
+				},
+				request: newRequest(
+					http.MethodGet,
+					"http://example.com/users/23",
+					http.Header{"Accept": {"text/html"}},
+				),
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeader:     wantHeader,
+			wantBody:       wantIndex,
+		},
+		{
+			name: "success with the matched ETag",
+			args: args{
+				fileSystem: newFileSystem(
+					"<html><head>" + RuntimeConfigPlaceholder + "</head></html>",
+				),
+				logger: new(MockLogger),
+				options: []StaticAssetOption{
+					WithRuntimeConfig(map[string]string{"apiURL": "/api/v1"}),
+				},
+				request: newRequest(
+					http.MethodGet,
+					"http://example.com/",
+					http.Header{"If-None-Match": {wantETag}},
+				),
+			},
+			wantStatusCode: http.StatusNotModified,
+			wantHeader:     http.Header{"Etag": {wantETag}},
+			wantBody:       "",
+		},
+		{
+			name: "success with the If-Modified-Since header",
+			args: args{
+				fileSystem: newFileSystem(
+					"<html><head>" + RuntimeConfigPlaceholder + "</head></html>",
+				),
+				logger: new(MockLogger),
+				options: []StaticAssetOption{
+					WithRuntimeConfig(map[string]string{"apiURL": "/api/v1"}),
+				},
+				request: newRequest(
+					http.MethodGet,
+					"http://example.com/",
+					http.Header{"If-Modified-Since": {modTime.Format(http.TimeFormat)}},
+				),
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeader:     wantHeader,
+			wantBody:       wantIndex,
+		},
+		{
+			name: "success with another file",
+			args: args{
+				fileSystem: newFileSystem(
+					"<html><head>" + RuntimeConfigPlaceholder + "</head></html>",
+				),
+				logger: new(MockLogger),
+				options: []StaticAssetOption{
+					WithRuntimeConfig(map[string]string{"apiURL": "/api/v1"}),
+				},
+				request: newRequest(http.MethodGet, "http://example.com/main.js", nil),
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeader: http.Header{
+				"Accept-Ranges":  {"bytes"},
+				"Content-Length": {"7"},
+				"Content-Type":   {"text/javascript; charset=utf-8"},
+				"Last-Modified":  {"Mon, 02 Jan 2006 15:04:05 GMT"},
+			},
+			wantBody: "main();",
+		},
+		{
+			name: "error with the injection",
+			args: args{
+				fileSystem: newFileSystem("<html></html>"),
+				logger: func() log.Logger {
+					logger := new(MockLogger)
+					logger.
+						On(
+							"Log",
+							"unable to render the index.html file: "+
+								"unable to inject the runtime config: "+
+								"there's neither the placeholder nor the head element",
+						).
+						Return()
+
+					return logger
+				}(),
+				options: []StaticAssetOption{
+					WithRuntimeConfig(map[string]string{"apiURL": "/api/v1"}),
+				},
+				request: newRequest(http.MethodGet, "http://example.com/", nil),
+			},
+			wantStatusCode: http.StatusInternalServerError,
+			wantHeader: http.Header{
+				"Content-Type":           {"text/plain; charset=utf-8"},
+				"X-Content-Type-Options": {"nosniff"},
+			},
+			wantBody: "Internal Server Error\n",
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			handler := StaticAssetHandler(
+				data.args.fileSystem,
+				data.args.logger,
+				data.args.options...,
+			)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, data.args.request)
+
+			mock.AssertExpectationsForObjects(test, data.args.logger)
+			assert.Equal(test, data.wantStatusCode, recorder.Code)
+			assert.Equal(test, data.wantHeader, recorder.Header())
+			assert.Equal(test, data.wantBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestStaticAssetHandler_withRuntimeConfigCaching(test *testing.T) {
+	fileSystem := fstest.MapFS{
+		"index.html": {Data: []byte("<head>" + RuntimeConfigPlaceholder)},
+	}
+	handler := StaticAssetHandler(
+		http.FS(fileSystem),
+		new(MockLogger),
+		WithRuntimeConfig(23),
+	)
+
+	var gotBodies []string
+	for _, index := range []string{"<head>", "<head>updated"} {
+		fileSystem["index.html"].Data = []byte(index + RuntimeConfigPlaceholder)
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+		handler.ServeHTTP(recorder, request)
+
+		gotBodies = append(gotBodies, recorder.Body.String())
+	}
+
+	wantBody := "<head><script>window.__CONFIG__ = 23;</script>"
+	assert.Equal(test, []string{wantBody, wantBody}, gotBodies)
+}
+
+func TestWithRuntimeConfigFromEnv(test *testing.T) {
+	test.Setenv("TEST_APP_API_URL", "/api/v1")
+	test.Setenv("TEST_APP_TITLE", "Title = Test")
+	test.Setenv("TEST_OTHER", "other")
+
+	var options staticAssetOptions
+	WithRuntimeConfigFromEnv("TEST_APP_")(&options)
+
+	wantConfig := map[string]string{"API_URL": "/api/v1", "TITLE": "Title = Test"}
+	assert.Equal(test, wantConfig, options.runtimeConfig)
+	assert.True(test, options.hasRuntimeConfig)
+}
+
+func TestWithRuntimeConfigFromEnv_withEmptyPrefix(test *testing.T) {
+	test.Setenv("TEST_SECRET", "secret")
+
+	logger := new(MockLogger)
+	logger.
+		On(
+			"Log",
+			"the runtime config is ignored: "+
+				"the prefix of the environment variables is empty",
+		).
+		Return()
+
+	index := "<head>" + RuntimeConfigPlaceholder
+	handler := StaticAssetHandler(
+		http.FS(fstest.MapFS{"index.html": {Data: []byte(index)}}),
+		logger,
+		WithRuntimeConfigFromEnv(""),
+	)
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+	handler.ServeHTTP(recorder, request)
+
+	mock.AssertExpectationsForObjects(test, logger)
+	assert.Equal(test, http.StatusOK, recorder.Code)
+	assert.Equal(test, index, recorder.Body.String())
+}
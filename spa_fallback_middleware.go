@@ -2,11 +2,96 @@ package httputils
 
 import (
 	"net/http"
+	"os"
+	"path"
+	"strings"
 
 	"github.com/golang/gddo/httputil/header"
 	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
 )
 
+// SPAFallbackOption ...
+//
+// It sets an optional parameter of the SPAFallbackMiddleware() function.
+//
+type SPAFallbackOption func(options *spaFallbackOptions)
+
+type spaFallbackOptions struct {
+	excludedPathPrefixes []string
+	indexPath            string
+	allowedMethods       []string
+	fileSystem           http.FileSystem
+}
+
+// WithExcludedPathPrefixes ...
+//
+// It excludes requests with the specified path prefixes (e.g. "/api", "/ws"
+// or "/metrics") from the fallback. A prefix matches whole path segments
+// only, i.e. the "/api" prefix matches the "/api" and "/api/v1" paths,
+// but not the "/apiary" one.
+//
+func WithExcludedPathPrefixes(prefixes ...string) SPAFallbackOption {
+	return func(options *spaFallbackOptions) {
+		options.excludedPathPrefixes =
+			append(options.excludedPathPrefixes, prefixes...)
+	}
+}
+
+// WithIndexPath ...
+//
+// It sets the path the requests are rewritten to. By default, it's "/".
+//
+// The http.FileServer() function redirects requests ending in "/index.html"
+// to the directory, so a rewritten request would be redirected in a loop.
+// Therefore, the path of the index.html file (e.g. "/app/index.html")
+// is replaced with the path of its directory (e.g. "/app/").
+//
+func WithIndexPath(indexPath string) SPAFallbackOption {
+	return func(options *spaFallbackOptions) {
+		if strings.HasSuffix(indexPath, "/"+indexFileName) {
+			indexPath = strings.TrimSuffix(indexPath, indexFileName)
+		}
+
+		options.indexPath = indexPath
+	}
+}
+
+// WithAllowedMethods ...
+//
+// It sets the methods of the requests that may be rewritten. By default,
+// they are the GET and HEAD methods.
+//
+func WithAllowedMethods(methods ...string) SPAFallbackOption {
+	return func(options *spaFallbackOptions) {
+		options.allowedMethods = methods
+	}
+}
+
+// WithFileSystem ...
+//
+// It enables the mode aware of existence of files in the file system.
+// In this mode, the Accept header is ignored, and the allowed requests
+// (see the WithAllowedMethods() and WithExcludedPathPrefixes() options)
+// are processed as follows:
+//
+//   - if the requested file (or directory) exists, the request is processed
+//     as usual;
+//   - if the requested file is missing and its name has an extension
+//     (e.g. "/main.abc.js"), the http.StatusNotFound status code is returned
+//     instead of the index.html file;
+//   - otherwise, the request is considered a route of the SPA and is rewritten
+//     to the index path.
+//
+// Errors of the file system other than the absence of a file are left
+// to the next handler, so the request is processed as usual.
+//
+func WithFileSystem(fileSystem http.FileSystem) SPAFallbackOption {
+	return func(options *spaFallbackOptions) {
+		options.fileSystem = fileSystem
+	}
+}
+
 // SPAFallbackMiddleware ...
 //
 // A SPA often manages its routing itself, so all relevant requests must
@@ -14,40 +99,127 @@ import (
 // for an API and distribution of static files.
 //
 // To separate these types of requests, the following heuristic is used.
-// If the request uses the GET method and contains the text/html value
-// in the Accept header, the index.html file is returned (more precisely, a path
-// part of a request URL is replaced to "/"). All other requests are processed
-// as usual. The fact is that any routing requests sent from a modern browser
-// meet described requirements.
+// If the request uses the GET or HEAD method and explicitly accepts
+// the text/html media type (i.e. with a non-zero q-value) in the Accept header,
+// the index.html file is returned (more precisely, a path part of a request URL
+// is replaced to the index path, "/" by default). All other requests
+// are processed as usual. The fact is that any routing requests sent
+// from a modern browser meet described requirements.
+//
+// The methods, the index path and the excluded path prefixes can be changed
+// via the options. See also the WithFileSystem() option for the mode aware
+// of existence of files.
 //
 // This solution is based on the proxy implementation in the development server
 // of the Create React App project. See:
 // https://create-react-app.dev/docs/proxying-api-requests-in-development/
 //
-func SPAFallbackMiddleware() mux.MiddlewareFunc {
+func SPAFallbackMiddleware(options ...SPAFallbackOption) mux.MiddlewareFunc {
+	spaFallbackOptions := newSPAFallbackOptions(options)
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(
 			writer http.ResponseWriter,
 			request *http.Request,
 		) {
-			if isStaticAssetRequest(request) {
-				request.URL.Path = "/"
+			var isFallbackRequired bool
+			if spaFallbackOptions.fileSystem == nil {
+				isFallbackRequired = isStaticAssetRequest(request, spaFallbackOptions)
+			} else if isFallbackAllowed(request, spaFallbackOptions) {
+				isFileExisting, err :=
+					checkFile(spaFallbackOptions.fileSystem, request.URL.Path)
+				switch {
+				case err != nil || isFileExisting:
+					// the request is processed as usual
+				case path.Ext(request.URL.Path) != "":
+					http.NotFound(writer, request)
+					return
+				default:
+					isFallbackRequired = true
+				}
+			}
+
+			if isFallbackRequired {
+				request.URL.Path = spaFallbackOptions.indexPath
+				request.URL.RawPath = ""
 			}
 			next.ServeHTTP(writer, request)
 		})
 	}
 }
 
-func isStaticAssetRequest(request *http.Request) bool {
-	if request.Method != http.MethodGet {
+func newSPAFallbackOptions(options []SPAFallbackOption) spaFallbackOptions {
+	spaFallbackOptions := spaFallbackOptions{
+		indexPath:      "/",
+		allowedMethods: []string{http.MethodGet, http.MethodHead},
+	}
+	for _, option := range options {
+		option(&spaFallbackOptions)
+	}
+
+	return spaFallbackOptions
+}
+
+func isStaticAssetRequest(
+	request *http.Request,
+	options spaFallbackOptions,
+) bool {
+	if !isFallbackAllowed(request, options) {
 		return false
 	}
 
 	for _, spec := range header.ParseAccept(request.Header, "Accept") {
-		if spec.Value == "text/html" {
+		if strings.EqualFold(spec.Value, "text/html") {
+			return spec.Q > 0
+		}
+	}
+
+	return false
+}
+
+func isFallbackAllowed(
+	request *http.Request,
+	options spaFallbackOptions,
+) bool {
+	if !isMethodAllowed(request.Method, options.allowedMethods) {
+		return false
+	}
+
+	for _, prefix := range options.excludedPathPrefixes {
+		if hasPathPrefix(request.URL.Path, prefix) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isMethodAllowed(method string, allowedMethods []string) bool {
+	for _, allowedMethod := range allowedMethods {
+		if method == allowedMethod {
 			return true
 		}
 	}
 
 	return false
 }
+
+func hasPathPrefix(path string, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// it cleans the path like the http.FileServer() function does
+// and then checks the existence of the corresponding file
+func checkFile(fileSystem http.FileSystem, filePath string) (bool, error) {
+	file, err := fileSystem.Open(path.Clean("/" + filePath))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, errors.Wrap(err, "unable to open the file")
+	}
+	file.Close() // nolint: errcheck
+
+	return true, nil
+}
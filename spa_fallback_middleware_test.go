@@ -1,10 +1,13 @@
 package httputils
 
 import (
+	"io/ioutil"
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
+	"testing/fstest"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -20,7 +23,8 @@ func ExampleSPAFallbackMiddleware() {
 
 func TestSPAFallbackMiddleware(test *testing.T) {
 	type middlewareArgs struct {
-		next http.Handler
+		options []SPAFallbackOption
+		next    http.Handler
 	}
 	type handlerArgs struct {
 		writer  http.ResponseWriter
@@ -92,6 +96,42 @@ func TestSPAFallbackMiddleware(test *testing.T) {
 				}(),
 			},
 		},
+		{
+			name: "static asset request/with the index path",
+			middlewareArgs: middlewareArgs{
+				options: []SPAFallbackOption{WithIndexPath("/app/")},
+				next: func() http.Handler {
+					request :=
+						httptest.NewRequest(http.MethodGet, "http://example.com/app/", nil)
+					request.Header.Set("Accept", "text/html")
+					request.RequestURI = "http://example.com/app/path"
+
+					handler := new(MockHandler)
+					handler.
+						On(
+							"ServeHTTP",
+							mock.MatchedBy(func(http.ResponseWriter) bool { return true }),
+							request,
+						).
+						Return()
+
+					return handler
+				}(),
+			},
+			handlerArgs: handlerArgs{
+				writer: new(MockResponseWriter),
+				request: func() *http.Request {
+					request := httptest.NewRequest(
+						http.MethodGet,
+						"http://example.com/app/path",
+						nil,
+					)
+					request.Header.Set("Accept", "text/html")
+
+					return request
+				}(),
+			},
+		},
 		{
 			name: "static asset request/to the root",
 			middlewareArgs: middlewareArgs{
@@ -123,7 +163,7 @@ func TestSPAFallbackMiddleware(test *testing.T) {
 		},
 	} {
 		test.Run(data.name, func(test *testing.T) {
-			middleware := SPAFallbackMiddleware()
+			middleware := SPAFallbackMiddleware(data.middlewareArgs.options...)
 			handler := middleware(data.middlewareArgs.next)
 			handler.ServeHTTP(data.handlerArgs.writer, data.handlerArgs.request)
 
@@ -136,9 +176,181 @@ func TestSPAFallbackMiddleware(test *testing.T) {
 	}
 }
 
+func TestSPAFallbackMiddleware_withFileSystem(test *testing.T) {
+	type args struct {
+		fileSystem http.FileSystem
+		request    *http.Request
+	}
+
+	for _, data := range []struct {
+		name           string
+		args           args
+		wantStatusCode int
+		wantPath       string
+	}{
+		{
+			name: "existing file",
+			args: args{
+				fileSystem: func() http.FileSystem {
+					file := new(MockFile)
+					file.On("Close").Return(nil)
+
+					fileSystem := new(MockFileSystem)
+					fileSystem.On("Open", "/main.abc.js").Return(file, nil)
+
+					return fileSystem
+				}(),
+				request: httptest.NewRequest(
+					http.MethodGet,
+					"http://example.com/main.abc.js",
+					nil,
+				),
+			},
+			wantStatusCode: http.StatusOK,
+			wantPath:       "/main.abc.js",
+		},
+		{
+			name: "missing file with an extension",
+			args: args{
+				fileSystem: func() http.FileSystem {
+					fileSystem := new(MockFileSystem)
+					fileSystem.On("Open", "/main.abc.js").Return(nil, os.ErrNotExist)
+
+					return fileSystem
+				}(),
+				request: func() *http.Request {
+					request := httptest.NewRequest(
+						http.MethodGet,
+						"http://example.com/main.abc.js",
+						nil,
+					)
+					request.Header.Set("Accept", "text/html")
+
+					return request
+				}(),
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantPath:       "",
+		},
+		{
+			name: "missing file without an extension",
+			args: args{
+				fileSystem: func() http.FileSystem {
+					fileSystem := new(MockFileSystem)
+					fileSystem.
+						On("Open", "/users/23").
+						Return(nil, &os.PathError{Op: "open", Err: os.ErrNotExist})
+
+					return fileSystem
+				}(),
+				request: httptest.NewRequest(
+					http.MethodGet,
+					"http://example.com/users/../users/23",
+					nil,
+				),
+			},
+			wantStatusCode: http.StatusOK,
+			wantPath:       "/",
+		},
+		{
+			name: "error with the file system",
+			args: args{
+				fileSystem: func() http.FileSystem {
+					fileSystem := new(MockFileSystem)
+					fileSystem.On("Open", "/users").Return(nil, os.ErrPermission)
+
+					return fileSystem
+				}(),
+				request: httptest.NewRequest(
+					http.MethodGet,
+					"http://example.com/users",
+					nil,
+				),
+			},
+			wantStatusCode: http.StatusOK,
+			wantPath:       "/users",
+		},
+		{
+			name: "excluded request",
+			args: args{
+				fileSystem: new(MockFileSystem),
+				request: httptest.NewRequest(
+					http.MethodGet,
+					"http://example.com/api/v1/endpoint.json",
+					nil,
+				),
+			},
+			wantStatusCode: http.StatusOK,
+			wantPath:       "/api/v1/endpoint.json",
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			var gotPath string
+			next := http.HandlerFunc(func(
+				writer http.ResponseWriter,
+				request *http.Request,
+			) {
+				gotPath = request.URL.Path
+			})
+
+			middleware := SPAFallbackMiddleware(
+				WithFileSystem(data.args.fileSystem),
+				WithExcludedPathPrefixes("/api"),
+			)
+			recorder := httptest.NewRecorder()
+			middleware(next).ServeHTTP(recorder, data.args.request)
+
+			mock.AssertExpectationsForObjects(test, data.args.fileSystem)
+			assert.Equal(test, data.wantStatusCode, recorder.Code)
+			assert.Equal(test, data.wantPath, gotPath)
+		})
+	}
+}
+
+func TestSPAFallbackMiddleware_withFileServer(test *testing.T) {
+	fileSystem := http.FS(fstest.MapFS{
+		"app/index.html": {Data: []byte("<html></html>")},
+	})
+
+	for _, data := range []struct {
+		name      string
+		indexPath string
+	}{
+		{
+			name:      "with the path of the directory",
+			indexPath: "/app/",
+		},
+		{
+			name:      "with the path of the index.html file",
+			indexPath: "/app/index.html",
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			handler := SPAFallbackMiddleware(
+				WithIndexPath(data.indexPath),
+			)(http.FileServer(fileSystem))
+
+			request :=
+				httptest.NewRequest(http.MethodGet, "http://example.com/app/path", nil)
+			request.Header.Set("Accept", "text/html")
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			response := recorder.Result()
+			responseBody, _ := ioutil.ReadAll(response.Body)
+			response.Body.Close()
+
+			assert.Equal(test, http.StatusOK, response.StatusCode)
+			assert.Equal(test, "<html></html>", string(responseBody))
+		})
+	}
+}
+
 func Test_isStaticAssetRequest(test *testing.T) {
 	type args struct {
 		request *http.Request
+		options []SPAFallbackOption
 	}
 
 	for _, data := range []struct {
@@ -242,6 +454,45 @@ func Test_isStaticAssetRequest(test *testing.T) {
 			},
 			want: assert.True,
 		},
+		{
+			name: "true/with the HEAD method",
+			args: args{
+				request: func() *http.Request {
+					request := httptest.NewRequest(http.MethodHead, "http://example.com/", nil)
+					request.Header.Set("Accept", "text/html")
+
+					return request
+				}(),
+			},
+			want: assert.True,
+		},
+		{
+			name: "true/with the allowed method",
+			args: args{
+				request: func() *http.Request {
+					request := httptest.NewRequest(http.MethodPost, "http://example.com/", nil)
+					request.Header.Set("Accept", "text/html")
+
+					return request
+				}(),
+				options: []SPAFallbackOption{WithAllowedMethods(http.MethodPost)},
+			},
+			want: assert.True,
+		},
+		{
+			name: "true/with the path similar to the excluded prefix",
+			args: args{
+				request: func() *http.Request {
+					request :=
+						httptest.NewRequest(http.MethodGet, "http://example.com/apiary", nil)
+					request.Header.Set("Accept", "text/html")
+
+					return request
+				}(),
+				options: []SPAFallbackOption{WithExcludedPathPrefixes("/api")},
+			},
+			want: assert.True,
+		},
 		{
 			name: "false/with an incorrect method",
 			args: args{
@@ -254,6 +505,64 @@ func Test_isStaticAssetRequest(test *testing.T) {
 			},
 			want: assert.False,
 		},
+		{
+			name: "false/with the disallowed method",
+			args: args{
+				request: func() *http.Request {
+					request := httptest.NewRequest(http.MethodHead, "http://example.com/", nil)
+					request.Header.Set("Accept", "text/html")
+
+					return request
+				}(),
+				options: []SPAFallbackOption{WithAllowedMethods(http.MethodGet)},
+			},
+			want: assert.False,
+		},
+		{
+			name: "false/with the excluded prefix",
+			args: args{
+				request: func() *http.Request {
+					request := httptest.NewRequest(
+						http.MethodGet,
+						"http://example.com/api/v1/endpoint",
+						nil,
+					)
+					request.Header.Set("Accept", "text/html")
+
+					return request
+				}(),
+				options: []SPAFallbackOption{
+					WithExcludedPathPrefixes("/ws", "/api/"),
+				},
+			},
+			want: assert.False,
+		},
+		{
+			name: "false/with the excluded path",
+			args: args{
+				request: func() *http.Request {
+					request :=
+						httptest.NewRequest(http.MethodGet, "http://example.com/metrics", nil)
+					request.Header.Set("Accept", "text/html")
+
+					return request
+				}(),
+				options: []SPAFallbackOption{WithExcludedPathPrefixes("/metrics")},
+			},
+			want: assert.False,
+		},
+		{
+			name: "false/with the zero q-value",
+			args: args{
+				request: func() *http.Request {
+					request := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+					request.Header.Set("Accept", "text/html;q=0, */*")
+
+					return request
+				}(),
+			},
+			want: assert.False,
+		},
 		{
 			name: "false/without the required header",
 			args: args{
@@ -299,7 +608,8 @@ func Test_isStaticAssetRequest(test *testing.T) {
 		},
 	} {
 		test.Run(data.name, func(test *testing.T) {
-			got := isStaticAssetRequest(data.args.request)
+			options := newSPAFallbackOptions(data.args.options)
+			got := isStaticAssetRequest(data.args.request, options)
 
 			data.want(test, got)
 		})
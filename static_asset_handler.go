@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/go-log/log"
+	"github.com/pkg/errors"
 )
 
 // StaticAssetHandler ...
@@ -11,11 +12,33 @@ import (
 // It's a complete analog of the http.FileServer() function with applied
 // SPAFallbackMiddleware() and CatchingMiddleware() middlewares.
 //
+// The optional runtime config can be injected into the index.html file (see
+// the WithRuntimeConfig() and WithRuntimeConfigFromEnv() options). An error
+// of these options is logged via the logger, and the handler works without
+// the runtime config then.
+//
 func StaticAssetHandler(
 	fileSystem http.FileSystem,
 	logger log.Logger,
+	options ...StaticAssetOption,
 ) http.Handler {
+	var staticAssetOptions staticAssetOptions
+	for _, option := range options {
+		option(&staticAssetOptions)
+	}
+
+	if err := staticAssetOptions.runtimeConfigErr; err != nil {
+		logger.Log(errors.Wrap(err, "the runtime config is ignored").Error())
+	}
+
 	handler := http.FileServer(fileSystem)
+	if staticAssetOptions.hasRuntimeConfig {
+		handler = runtimeConfigMiddleware(
+			fileSystem,
+			logger,
+			staticAssetOptions.runtimeConfig,
+		)(handler)
+	}
 	handler = SPAFallbackMiddleware()(handler)
 	handler = CatchingMiddleware(logger)(handler)
 
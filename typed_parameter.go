@@ -0,0 +1,271 @@
+package httputils
+
+import (
+	"cmp"
+	"net/http"
+	"reflect"
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
+const headerParameterSource = "header"
+
+// ParamOption ...
+//
+// It sets an optional parameter of the PathParam(), QueryParam()
+// and HeaderParam() functions.
+//
+type ParamOption[T any] func(options *paramOptions[T])
+
+type paramOptions[T any] struct {
+	required            bool
+	defaultValue        T
+	hasDefaultValue     bool
+	pattern             *regexp.Regexp
+	validators          []func(value T) error
+	pathParameterSource PathParameterSource
+}
+
+// WithRequiredParam ...
+//
+// It makes the parameter required: if it's missing, the ErrMissingParameter
+// error is returned (in a wrapped form). Path parameters are always required.
+//
+func WithRequiredParam[T any]() ParamOption[T] {
+	return func(options *paramOptions[T]) {
+		options.required = true
+	}
+}
+
+// WithDefaultParam ...
+//
+// It sets the value that is returned if the parameter is missing.
+// It takes precedence over the WithRequiredParam() option. The value isn't
+// checked by the constraints.
+//
+func WithDefaultParam[T any](defaultValue T) ParamOption[T] {
+	return func(options *paramOptions[T]) {
+		options.defaultValue = defaultValue
+		options.hasDefaultValue = true
+	}
+}
+
+// WithMinParam ...
+//
+// It requires the parsed value to be greater than or equal to the minimum.
+//
+func WithMinParam[T cmp.Ordered](minimum T) ParamOption[T] {
+	return withParamValidator(func(value T) error {
+		if value < minimum {
+			return errors.Errorf("the value %v is less than %v", value, minimum)
+		}
+
+		return nil
+	})
+}
+
+// WithMaxParam ...
+//
+// It requires the parsed value to be less than or equal to the maximum.
+//
+func WithMaxParam[T cmp.Ordered](maximum T) ParamOption[T] {
+	return withParamValidator(func(value T) error {
+		if value > maximum {
+			return errors.Errorf("the value %v is greater than %v", value, maximum)
+		}
+
+		return nil
+	})
+}
+
+// WithEnumParam ...
+//
+// It requires the parsed value to be equal to one of the allowed values.
+//
+func WithEnumParam[T comparable](allowedValues ...T) ParamOption[T] {
+	return withParamValidator(func(value T) error {
+		for _, allowedValue := range allowedValues {
+			if value == allowedValue {
+				return nil
+			}
+		}
+
+		return errors.Errorf("the value %v isn't one of %v", value, allowedValues)
+	})
+}
+
+// WithPatternParam ...
+//
+// It requires each raw value of the parameter to match the pattern
+// before the parsing. The type can't be inferred, so it should be specified
+// explicitly, e.g.:
+//
+//	WithPatternParam[string](regexp.MustCompile(`^[a-z]+$`))
+//
+func WithPatternParam[T any](pattern *regexp.Regexp) ParamOption[T] {
+	return func(options *paramOptions[T]) {
+		options.pattern = pattern
+	}
+}
+
+// WithPathParamSource ...
+//
+// It sets the source of path parameters for the PathParam() function.
+// By default, it's the MuxPathParameterSource variable.
+//
+func WithPathParamSource[T any](source PathParameterSource) ParamOption[T] {
+	return func(options *paramOptions[T]) {
+		options.pathParameterSource = source
+	}
+}
+
+// PathParam ...
+//
+// It extracts the path parameter with the specified name via the source
+// of path parameters (see the WithPathParamSource() option) and then parses
+// it into a value of the specified type.
+//
+// See the QueryParam() function for details of the parsing, the constraints
+// and the errors.
+//
+func PathParam[T any](
+	request *http.Request,
+	name string,
+	options ...ParamOption[T],
+) (T, error) {
+	paramOptions := newParamOptions(options)
+	paramOptions.required = true
+
+	var values []string
+	source := paramOptions.pathParameterSource
+	if value, ok := source.PathParameter(request, name); ok {
+		values = []string{value}
+	}
+
+	return parseParam(pathParameterSource, name, values, paramOptions)
+}
+
+// QueryParam ...
+//
+// It extracts the parameter with the specified name from the query part
+// of the request URL and then parses it into a value of the specified type.
+//
+// The parsing works like in the BindRequest() function, i.e. it supports
+// the registered parsers, the encoding.TextUnmarshaler interface, the basic
+// types, pointers to them and slices of them (filled with all values
+// of the parameter). Values are parsed strictly, i.e. trailing input
+// is an error.
+//
+// By default, the parameter is optional: if it's missing, the zero value
+// is returned; see also the WithRequiredParam() and WithDefaultParam()
+// options. The constraints (see the WithMinParam(), WithMaxParam(),
+// WithEnumParam() and WithPatternParam() options) are checked after
+// the parsing.
+//
+// Errors related to the parameter are returned as the ParameterError error,
+// so they carry the name and the location of the parameter and are resolved
+// to the http.StatusBadRequest status code (see the ResolveHTTPError()
+// function). If the type isn't supported, the ErrUnsupportedType error
+// is returned (in a wrapped form).
+//
+func QueryParam[T any](
+	request *http.Request,
+	name string,
+	options ...ParamOption[T],
+) (T, error) {
+	values := request.URL.Query()[name]
+	paramOptions := newParamOptions(options)
+	return parseParam(queryParameterSource, name, values, paramOptions)
+}
+
+// HeaderParam ...
+//
+// It extracts the header with the specified name and then parses it
+// into a value of the specified type.
+//
+// See the QueryParam() function for details of the parsing, the constraints
+// and the errors.
+//
+func HeaderParam[T any](
+	request *http.Request,
+	name string,
+	options ...ParamOption[T],
+) (T, error) {
+	values := request.Header.Values(name)
+	paramOptions := newParamOptions(options)
+	return parseParam(headerParameterSource, name, values, paramOptions)
+}
+
+func withParamValidator[T any](validator func(value T) error) ParamOption[T] {
+	return func(options *paramOptions[T]) {
+		options.validators = append(options.validators, validator)
+	}
+}
+
+func newParamOptions[T any](options []ParamOption[T]) paramOptions[T] {
+	paramOptions := paramOptions[T]{pathParameterSource: MuxPathParameterSource}
+	for _, option := range options {
+		option(&paramOptions)
+	}
+
+	return paramOptions
+}
+
+func parseParam[T any](
+	source string,
+	name string,
+	values []string,
+	options paramOptions[T],
+) (T, error) {
+	var zeroValue T
+	if len(values) == 0 {
+		switch {
+		case options.hasDefaultValue:
+			return options.defaultValue, nil
+		case options.required:
+			return zeroValue, ParameterError{
+				Source: source,
+				Name:   name,
+				Err:    ErrMissingParameter,
+			}
+		default:
+			return zeroValue, nil
+		}
+	}
+
+	if options.pattern != nil {
+		for _, value := range values {
+			if !options.pattern.MatchString(value) {
+				err := errors.Errorf(
+					"the value %q doesn't match the pattern %q",
+					value,
+					options.pattern,
+				)
+				return zeroValue, ParameterError{Source: source, Name: name, Err: err}
+			}
+		}
+	}
+
+	var parsedValue T
+	err := parseParameterValues(values, reflect.ValueOf(&parsedValue).Elem())
+	if errors.Is(err, ErrUnsupportedType) {
+		return zeroValue, errors.Wrapf(
+			err,
+			"unable to parse the %s parameter %q",
+			source,
+			name,
+		)
+	}
+	if err != nil {
+		return zeroValue, ParameterError{Source: source, Name: name, Err: err}
+	}
+
+	for _, validator := range options.validators {
+		if err := validator(parsedValue); err != nil {
+			return zeroValue, ParameterError{Source: source, Name: name, Err: err}
+		}
+	}
+
+	return parsedValue, nil
+}
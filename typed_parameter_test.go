@@ -0,0 +1,293 @@
+package httputils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathParam(test *testing.T) {
+	type args struct {
+		request *http.Request
+		name    string
+		options []ParamOption[int]
+	}
+
+	for _, data := range []struct {
+		name      string
+		args      args
+		wantValue int
+		wantErr   string
+	}{
+		{
+			name: "success",
+			args: args{
+				request: mux.SetURLVars(
+					httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
+					map[string]string{"id": "23"},
+				),
+				name:    "id",
+				options: []ParamOption[int]{WithMinParam(1)},
+			},
+			wantValue: 23,
+			wantErr:   "",
+		},
+		{
+			name: "success with the path parameter source",
+			args: args{
+				request: httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
+				name:    "id",
+				options: []ParamOption[int]{
+					WithPathParamSource[int](MapPathParameterSource{"id": "23"}),
+				},
+			},
+			wantValue: 23,
+			wantErr:   "",
+		},
+		{
+			name: "success with the default value",
+			args: args{
+				request: httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
+				name:    "id",
+				options: []ParamOption[int]{WithDefaultParam(42)},
+			},
+			wantValue: 42,
+			wantErr:   "",
+		},
+		{
+			name: "error with the parameter existence",
+			args: args{
+				request: httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
+				name:    "id",
+			},
+			wantValue: 0,
+			wantErr:   `path parameter "id": the parameter is missing`,
+		},
+		{
+			name: "error with the parsing",
+			args: args{
+				request: mux.SetURLVars(
+					httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
+					map[string]string{"id": "12abc"},
+				),
+				name: "id",
+			},
+			wantValue: 0,
+			wantErr: `path parameter "id": strconv.ParseInt: ` +
+				`parsing "12abc": invalid syntax`,
+		},
+		{
+			name: "error with the constraint",
+			args: args{
+				request: mux.SetURLVars(
+					httptest.NewRequest(http.MethodGet, "http://example.com/", nil),
+					map[string]string{"id": "0"},
+				),
+				name:    "id",
+				options: []ParamOption[int]{WithMinParam(1)},
+			},
+			wantValue: 0,
+			wantErr:   `path parameter "id": the value 0 is less than 1`,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			gotValue, gotErr :=
+				PathParam(data.args.request, data.args.name, data.args.options...)
+
+			assert.Equal(test, data.wantValue, gotValue)
+			if data.wantErr == "" {
+				assert.NoError(test, gotErr)
+				return
+			}
+
+			assert.EqualError(test, gotErr, data.wantErr)
+			assert.Equal(
+				test,
+				http.StatusBadRequest,
+				ResolveHTTPError(gotErr).StatusCode(),
+			)
+		})
+	}
+}
+
+func TestQueryParam(test *testing.T) {
+	test.Run("success with an integer", func(test *testing.T) {
+		request := httptest.NewRequest(
+			http.MethodGet,
+			"http://example.com/?limit=10&limit=20",
+			nil,
+		)
+		gotValue, gotErr := QueryParam(
+			request,
+			"limit",
+			WithMinParam(1),
+			WithMaxParam(100),
+			WithRequiredParam[int](),
+		)
+
+		assert.Equal(test, 10, gotValue)
+		assert.NoError(test, gotErr)
+	})
+
+	test.Run("success with a slice", func(test *testing.T) {
+		request := httptest.NewRequest(
+			http.MethodGet,
+			"http://example.com/?tag=one&tag=two",
+			nil,
+		)
+		gotValue, gotErr := QueryParam(
+			request,
+			"tag",
+			WithPatternParam[[]string](regexp.MustCompile(`^[a-z]+$`)),
+		)
+
+		assert.Equal(test, []string{"one", "two"}, gotValue)
+		assert.NoError(test, gotErr)
+	})
+
+	test.Run("success with the missing parameter", func(test *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+		gotValue, gotErr := QueryParam(request, "limit", WithMinParam(1))
+
+		assert.Equal(test, 0, gotValue)
+		assert.NoError(test, gotErr)
+	})
+
+	test.Run("success with the enumeration", func(test *testing.T) {
+		request := httptest.NewRequest(
+			http.MethodGet,
+			"http://example.com/?order=desc",
+			nil,
+		)
+		gotValue, gotErr :=
+			QueryParam(request, "order", WithEnumParam("asc", "desc"))
+
+		assert.Equal(test, "desc", gotValue)
+		assert.NoError(test, gotErr)
+	})
+
+	for _, data := range []struct {
+		name    string
+		url     string
+		options []ParamOption[int]
+		wantErr string
+	}{
+		{
+			name:    "error with the parameter existence",
+			url:     "http://example.com/",
+			options: []ParamOption[int]{WithRequiredParam[int]()},
+			wantErr: `query parameter "limit": the parameter is missing`,
+		},
+		{
+			name:    "error with the minimum",
+			url:     "http://example.com/?limit=0",
+			options: []ParamOption[int]{WithMinParam(1)},
+			wantErr: `query parameter "limit": the value 0 is less than 1`,
+		},
+		{
+			name:    "error with the maximum",
+			url:     "http://example.com/?limit=101",
+			options: []ParamOption[int]{WithMaxParam(100)},
+			wantErr: `query parameter "limit": the value 101 is greater than 100`,
+		},
+		{
+			name:    "error with the enumeration",
+			url:     "http://example.com/?limit=15",
+			options: []ParamOption[int]{WithEnumParam(10, 20, 50)},
+			wantErr: `query parameter "limit": the value 15 isn't one of [10 20 50]`,
+		},
+		{
+			name: "error with the pattern",
+			url:  "http://example.com/?limit=+10",
+			options: []ParamOption[int]{
+				WithPatternParam[int](regexp.MustCompile(`^\d+$`)),
+			},
+			wantErr: `query parameter "limit": ` +
+				`the value " 10" doesn't match the pattern "^\\d+$"`,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, data.url, nil)
+			gotValue, gotErr := QueryParam(request, "limit", data.options...)
+
+			assert.Equal(test, 0, gotValue)
+			assert.EqualError(test, gotErr, data.wantErr)
+
+			var parameterErr ParameterError
+			if assert.True(test, errors.As(gotErr, &parameterErr)) {
+				assert.Equal(test, queryParameterSource, parameterErr.Source)
+				assert.Equal(test, "limit", parameterErr.Name)
+			}
+		})
+	}
+
+	test.Run("error with the unsupported type", func(test *testing.T) {
+		request := httptest.NewRequest(
+			http.MethodGet,
+			"http://example.com/?data=test",
+			nil,
+		)
+		gotValue, gotErr := QueryParam[map[string]string](request, "data")
+
+		assert.Nil(test, gotValue)
+		assert.True(test, errors.Is(gotErr, ErrUnsupportedType))
+		assert.Equal(
+			test,
+			http.StatusInternalServerError,
+			ResolveHTTPError(gotErr).StatusCode(),
+		)
+	})
+}
+
+func TestHeaderParam(test *testing.T) {
+	test.Run("success", func(test *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+		request.Header.Set("X-Timeout", "1m30s")
+
+		gotValue, gotErr := HeaderParam(
+			request,
+			"X-Timeout",
+			WithMaxParam(time.Hour),
+		)
+
+		assert.Equal(test, 90*time.Second, gotValue)
+		assert.NoError(test, gotErr)
+	})
+
+	test.Run("success with the default value", func(test *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+		gotValue, gotErr := HeaderParam(
+			request,
+			"X-Timeout",
+			WithDefaultParam(time.Minute),
+		)
+
+		assert.Equal(test, time.Minute, gotValue)
+		assert.NoError(test, gotErr)
+	})
+
+	test.Run("error", func(test *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+		request.Header.Set("X-Timeout", "1")
+
+		gotValue, gotErr := HeaderParam[time.Duration](request, "X-Timeout")
+
+		assert.Equal(test, time.Duration(0), gotValue)
+		assert.EqualError(
+			test,
+			gotErr,
+			`header parameter "X-Timeout": time: missing unit in duration "1"`,
+		)
+		assert.Equal(
+			test,
+			http.StatusBadRequest,
+			ResolveHTTPError(gotErr).StatusCode(),
+		)
+	})
+}
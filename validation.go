@@ -0,0 +1,144 @@
+package httputils
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Validator ...
+//
+// It's implemented by data that can validate itself. It's used
+// by the ReadJSONWithOptions() function.
+//
+type Validator interface {
+	Validate() error
+}
+
+// ValidatorFunc ...
+//
+// It validates the data. See the WithValidator() option.
+//
+type ValidatorFunc func(data interface{}) error
+
+// FieldError ...
+//
+// It describes a problem with the field at the specified path
+// (e.g. "items[0].name").
+//
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError ...
+//
+// It lists problems with fields of the data. It's resolved
+// to the http.StatusUnprocessableEntity status code (see
+// the ResolveHTTPError() function), and the problems are rendered
+// as the "errors" member by the WriteJSONError() and WriteProblem() functions.
+//
+type ValidationError struct {
+	Errors []FieldError
+}
+
+// Add ...
+//
+// It adds the problem with the field.
+//
+func (err *ValidationError) Add(field string, message string) {
+	err.Errors = append(err.Errors, FieldError{Field: field, Message: message})
+}
+
+// ErrorOrNil ...
+//
+// It returns the ValidationError object as the error if there're problems
+// with fields; otherwise, it returns nil. It's useful for implementing
+// of the Validator interface.
+//
+func (err ValidationError) ErrorOrNil() error {
+	if len(err.Errors) == 0 {
+		return nil
+	}
+
+	return err
+}
+
+// StatusCode ...
+//
+// It returns the http.StatusUnprocessableEntity status code. It implements
+// the StatusCodeProvider interface.
+//
+func (err ValidationError) StatusCode() int {
+	return http.StatusUnprocessableEntity
+}
+
+// Problem ...
+//
+// It returns problem details with the "errors" extension member. It implements
+// the ProblemProvider interface.
+//
+func (err ValidationError) Problem() Problem {
+	return Problem{
+		Status:     err.StatusCode(),
+		Extensions: map[string]interface{}{"errors": err.Errors},
+	}
+}
+
+func (err ValidationError) Error() string {
+	messages := make([]string, 0, len(err.Errors))
+	for _, fieldErr := range err.Errors {
+		messages = append(messages, fieldErr.Field+": "+fieldErr.Message)
+	}
+
+	return "the data is invalid: " + strings.Join(messages, "; ")
+}
+
+func validate(data interface{}, validator ValidatorFunc) error {
+	var err error
+	if dataValidator, ok := data.(Validator); ok {
+		err = dataValidator.Validate()
+	}
+	if err == nil && validator != nil {
+		err = validator(data)
+	}
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := asValidationError(err); !ok {
+		err = UnprocessableEntity(err)
+	}
+
+	return errors.Wrap(err, "unable to validate the data")
+}
+
+func fieldErrors(err error) []FieldError {
+	if validationErr, ok := asValidationError(err); ok {
+		return validationErr.Errors
+	}
+
+	var bindingErr BindingError
+	if errors.As(err, &bindingErr) {
+		return bindingErr.fieldErrors()
+	}
+
+	return nil
+}
+
+// it finds the ValidationError error in the chain, both by value
+// and by pointer
+func asValidationError(err error) (ValidationError, bool) {
+	var validationErr ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr, true
+	}
+
+	var validationErrPointer *ValidationError
+	if errors.As(err, &validationErrPointer) && validationErrPointer != nil {
+		return *validationErrPointer, true
+	}
+
+	return ValidationError{}, false
+}
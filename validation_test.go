@@ -0,0 +1,193 @@
+package httputils
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type testValidatedData struct {
+	Name string
+	Tags []string
+}
+
+func (data testValidatedData) Validate() error {
+	var validationErr ValidationError
+	if data.Name == "" {
+		validationErr.Add("name", "is required")
+	}
+	for index, tag := range data.Tags {
+		if tag == "" {
+			validationErr.Add(fmt.Sprintf("tags[%d]", index), "is empty")
+		}
+	}
+
+	return validationErr.ErrorOrNil()
+}
+
+func TestValidationError_ErrorOrNil(test *testing.T) {
+	for _, data := range []struct {
+		name          string
+		validationErr ValidationError
+		wantErr       assert.ErrorAssertionFunc
+	}{
+		{
+			name:          "without problems",
+			validationErr: ValidationError{},
+			wantErr:       assert.NoError,
+		},
+		{
+			name: "with problems",
+			validationErr: ValidationError{
+				Errors: []FieldError{{Field: "name", Message: "is required"}},
+			},
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			gotErr := data.validationErr.ErrorOrNil()
+
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestValidationError_Error(test *testing.T) {
+	validationErr := ValidationError{
+		Errors: []FieldError{
+			{Field: "name", Message: "is required"},
+			{Field: "tags[1]", Message: "is empty"},
+		},
+	}
+	got := validationErr.Error()
+
+	want := "the data is invalid: name: is required; tags[1]: is empty"
+	assert.Equal(test, want, got)
+}
+
+func TestValidationError_Problem(test *testing.T) {
+	fieldErrs := []FieldError{{Field: "name", Message: "is required"}}
+	got := NewProblem(
+		errors.Wrap(ValidationError{Errors: fieldErrs}, "dummy"),
+		http.StatusInternalServerError,
+	)
+
+	want := Problem{
+		Type:       "about:blank",
+		Title:      "Unprocessable Entity",
+		Status:     http.StatusUnprocessableEntity,
+		Detail:     "dummy: the data is invalid: name: is required",
+		Extensions: map[string]interface{}{"errors": fieldErrs},
+	}
+	assert.Equal(test, want, got)
+}
+
+func TestReadJSONWithOptions_withValidation(test *testing.T) {
+	type args struct {
+		data    string
+		options []ReadJSONOption
+	}
+
+	for _, data := range []struct {
+		name            string
+		args            args
+		wantErr         assert.ErrorAssertionFunc
+		wantStatusCode  int
+		wantFieldErrors []FieldError
+	}{
+		{
+			name: "success",
+			args: args{
+				data: `{"Name": "test", "Tags": ["one", "two"]}`,
+				options: []ReadJSONOption{
+					WithValidator(func(data interface{}) error { return nil }),
+				},
+			},
+			wantErr:         assert.NoError,
+			wantStatusCode:  0,
+			wantFieldErrors: nil,
+		},
+		{
+			name: "error with the Validator interface",
+			args: args{
+				data:    `{"Name": "", "Tags": ["one", ""]}`,
+				options: nil,
+			},
+			wantErr:        assert.Error,
+			wantStatusCode: http.StatusUnprocessableEntity,
+			wantFieldErrors: []FieldError{
+				{Field: "name", Message: "is required"},
+				{Field: "tags[1]", Message: "is empty"},
+			},
+		},
+		{
+			name: "error with the validator function/validation error",
+			args: args{
+				data: `{"Name": "test"}`,
+				options: []ReadJSONOption{
+					WithValidator(func(data interface{}) error {
+						var validationErr ValidationError
+						if len(data.(*testValidatedData).Tags) == 0 {
+							validationErr.Add("tags", "is empty")
+						}
+
+						return validationErr.ErrorOrNil()
+					}),
+				},
+			},
+			wantErr:         assert.Error,
+			wantStatusCode:  http.StatusUnprocessableEntity,
+			wantFieldErrors: []FieldError{{Field: "tags", Message: "is empty"}},
+		},
+		{
+			name: "error with the validator function/validation error by pointer",
+			args: args{
+				data: `{"Name": "test"}`,
+				options: []ReadJSONOption{
+					WithValidator(func(data interface{}) error {
+						return &ValidationError{
+							Errors: []FieldError{{Field: "tags", Message: "is empty"}},
+						}
+					}),
+				},
+			},
+			wantErr:         assert.Error,
+			wantStatusCode:  http.StatusUnprocessableEntity,
+			wantFieldErrors: []FieldError{{Field: "tags", Message: "is empty"}},
+		},
+		{
+			name: "error with the validator function/plain error",
+			args: args{
+				data: `{"Name": "test"}`,
+				options: []ReadJSONOption{
+					WithValidator(func(data interface{}) error {
+						return iotest.ErrTimeout
+					}),
+				},
+			},
+			wantErr:         assert.Error,
+			wantStatusCode:  http.StatusUnprocessableEntity,
+			wantFieldErrors: nil,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			err := ReadJSONWithOptions(
+				strings.NewReader(data.args.data),
+				new(testValidatedData),
+				data.args.options...,
+			)
+
+			data.wantErr(test, err)
+			if err != nil {
+				gotStatusCode := ResolveHTTPError(err).StatusCode()
+				assert.Equal(test, data.wantStatusCode, gotStatusCode)
+				assert.Equal(test, data.wantFieldErrors, fieldErrors(err))
+			}
+		})
+	}
+}